@@ -0,0 +1,16 @@
+package monotime
+
+import "golang.org/x/sys/unix"
+
+// alarmClock is CLOCK_BOOTTIME_ALARM: CLOCK_BOOTTIME, whose timers also wake
+// the system from suspend.
+var alarmClock = kernelClock{newAlarmTimer, NowBoottime}
+
+func init() {
+	timerClocks[ClockBoottimeAlarm] = alarmClock
+}
+
+// newAlarmTimer returns a kernelTimer on CLOCK_BOOTTIME_ALARM.
+func newAlarmTimer() (*kernelTimer, error) {
+	return newTimerfd(unix.CLOCK_BOOTTIME_ALARM)
+}
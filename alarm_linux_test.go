@@ -0,0 +1,32 @@
+package monotime
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+func TestAlarmTicker(t *testing.T) {
+	ticker, err := NewTickerErr(time.Millisecond, WithClock(ClockBoottimeAlarm))
+	if CheckWakeAlarm() != nil {
+		var capErr *CapabilityError
+		if !errors.As(err, &capErr) {
+			t.Fatalf("alarm ticker without CAP_WAKE_ALARM: err = %v, want a *CapabilityError", err)
+		}
+		return
+	}
+	if errors.Is(err, unix.EINVAL) {
+		t.Skip("no alarm timers on this host:", err)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ticker.Stop()
+	select {
+	case <-ticker.C:
+	case <-time.After(time.Second):
+		t.Fatal("alarm ticker did not tick")
+	}
+}
@@ -0,0 +1,32 @@
+package monotime
+
+import "time"
+
+// WallAnchor pins a monotonic Time to the wall clock time read at the same
+// instant, so monotonic timestamps can be shown to people, or lined up with
+// logs stamped on the wall clock. The mapping holds only while the wall clock
+// isn't stepped; take a new anchor after a step, as WatchWallClock reports on
+// Linux.
+type WallAnchor struct {
+	Mono Time
+	Wall time.Time
+}
+
+// NewWallAnchor returns an anchor for the current instant, reading the wall
+// clock between two monotonic readings and taking their midpoint.
+func NewWallAnchor() WallAnchor {
+	before := now()
+	wall := time.Now().Round(0)
+	after := now()
+	return WallAnchor{Mono: before.Add(after.Sub(before) / 2), Wall: wall}
+}
+
+// ToWall returns the wall clock time of the monotonic time t.
+func (a WallAnchor) ToWall(t Time) time.Time {
+	return a.Wall.Add(t.Sub(a.Mono))
+}
+
+// FromWall returns the monotonic time of the wall clock time w.
+func (a WallAnchor) FromWall(w time.Time) Time {
+	return a.Mono.Add(w.Sub(a.Wall))
+}
@@ -0,0 +1,25 @@
+package monotime
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWallAnchor(t *testing.T) {
+	a := NewWallAnchor()
+	mono := a.Mono.Add(90 * time.Minute)
+	wall := a.ToWall(mono)
+	if d := wall.Sub(a.Wall); d != 90*time.Minute {
+		t.Errorf("ToWall moved %v, want 1h30m", d)
+	}
+	if got := a.FromWall(wall); got != mono {
+		t.Errorf("FromWall(ToWall(%d)) = %d", mono, got)
+	}
+
+	// A fresh anchor agrees with an old one while the clock isn't stepped.
+	time.Sleep(10 * time.Millisecond)
+	b := NewWallAnchor()
+	if d := a.ToWall(b.Mono).Sub(b.Wall); d < -time.Millisecond || d > time.Millisecond {
+		t.Errorf("anchors taken 10ms apart disagree by %v", d)
+	}
+}
@@ -0,0 +1,24 @@
+package monotime
+
+// BackendInfo describes the mechanisms the package is using on this host.
+// Its String form is meant for logs and bug reports.
+type BackendInfo struct {
+	// OS and Arch are the GOOS and GOARCH the package was built for.
+	OS, Arch string
+	// Now is how Now reads the clock.
+	Now string
+	// Timer is the kernel mechanism behind Ticker.
+	Timer string
+	// Sleep is how SleepUntil waits.
+	Sleep string
+	// Clocksource is the kernel's current clocksource, if known.
+	Clocksource string
+}
+
+func (b BackendInfo) String() string {
+	s := b.OS + "/" + b.Arch + " now=" + b.Now + " timer=" + b.Timer + " sleep=" + b.Sleep
+	if b.Clocksource != "" {
+		s += " clocksource=" + b.Clocksource
+	}
+	return s
+}
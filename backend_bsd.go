@@ -0,0 +1,21 @@
+//go:build dragonfly || freebsd || netbsd || openbsd
+// +build dragonfly freebsd netbsd openbsd
+
+package monotime
+
+import "runtime"
+
+// Backend reports the mechanisms the package is using on this host.
+func Backend() BackendInfo {
+	timer := "kqueue(EVFILT_TIMER)"
+	if kqTimerFflags != 0 {
+		timer = "kqueue(EVFILT_TIMER, NOTE_NSECONDS)"
+	}
+	return BackendInfo{
+		OS:    runtime.GOOS,
+		Arch:  runtime.GOARCH,
+		Now:   "clock_gettime(CLOCK_MONOTONIC)",
+		Timer: timer,
+		Sleep: "time.Sleep",
+	}
+}
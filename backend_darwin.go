@@ -0,0 +1,14 @@
+package monotime
+
+import "runtime"
+
+// Backend reports the mechanisms the package is using on this host.
+func Backend() BackendInfo {
+	return BackendInfo{
+		OS:    runtime.GOOS,
+		Arch:  runtime.GOARCH,
+		Now:   "clock_gettime(CLOCK_UPTIME_RAW)",
+		Timer: "kqueue(EVFILT_TIMER, NOTE_NSECONDS)",
+		Sleep: "time.Sleep",
+	}
+}
@@ -0,0 +1,17 @@
+//go:build !darwin && !dragonfly && !freebsd && !js && !linux && !netbsd && !openbsd && !windows
+// +build !darwin,!dragonfly,!freebsd,!js,!linux,!netbsd,!openbsd,!windows
+
+package monotime
+
+import "runtime"
+
+// Backend reports the mechanisms the package is using on this host.
+func Backend() BackendInfo {
+	return BackendInfo{
+		OS:    runtime.GOOS,
+		Arch:  runtime.GOARCH,
+		Now:   "runtime nanotime",
+		Timer: "runtime timer",
+		Sleep: "time.Sleep",
+	}
+}
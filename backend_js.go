@@ -0,0 +1,14 @@
+package monotime
+
+import "runtime"
+
+// Backend reports the mechanisms the package is using on this host.
+func Backend() BackendInfo {
+	return BackendInfo{
+		OS:    runtime.GOOS,
+		Arch:  runtime.GOARCH,
+		Now:   "performance.now()",
+		Timer: "setTimeout",
+		Sleep: "time.Sleep",
+	}
+}
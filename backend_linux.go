@@ -0,0 +1,15 @@
+package monotime
+
+import "runtime"
+
+// Backend reports the mechanisms the package is using on this host.
+func Backend() BackendInfo {
+	return BackendInfo{
+		OS:          runtime.GOOS,
+		Arch:        runtime.GOARCH,
+		Now:         "clock_gettime(CLOCK_MONOTONIC)",
+		Timer:       "timerfd(CLOCK_MONOTONIC)",
+		Sleep:       "clock_nanosleep(CLOCK_MONOTONIC, TIMER_ABSTIME)",
+		Clocksource: Capabilities().Clocksource,
+	}
+}
@@ -0,0 +1,20 @@
+package monotime
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestBackend(t *testing.T) {
+	b := Backend()
+	if b.OS != runtime.GOOS || b.Arch != runtime.GOARCH {
+		t.Errorf("Backend() is for %s/%s, built for %s/%s", b.OS, b.Arch, runtime.GOOS, runtime.GOARCH)
+	}
+	if b.Now == "" || b.Timer == "" || b.Sleep == "" {
+		t.Errorf("Backend() = %+v, missing a mechanism", b)
+	}
+	if s := b.String(); !strings.HasPrefix(s, runtime.GOOS+"/"+runtime.GOARCH+" now=") {
+		t.Errorf("Backend().String() = %q", s)
+	}
+}
@@ -0,0 +1,44 @@
+package monotime
+
+import (
+	"runtime"
+	"sync"
+)
+
+var (
+	waitableOnce sync.Once
+	waitableKind string
+)
+
+// Backend reports the mechanisms the package is using on this host.
+func Backend() BackendInfo {
+	b := BackendInfo{
+		OS:    runtime.GOOS,
+		Arch:  runtime.GOARCH,
+		Now:   "QueryPerformanceCounter",
+		Timer: waitableTimerKind(),
+		Sleep: "time.Sleep",
+	}
+	if hasUnbiasedTime {
+		b.Now = "QueryUnbiasedInterruptTimePrecise"
+	}
+	return b
+}
+
+// waitableTimerKind describes the waitable timers the kernel hands out, by
+// creating one.
+func waitableTimerKind() string {
+	waitableOnce.Do(func() {
+		waitableKind = "CreateWaitableTimerEx(HIGH_RESOLUTION)"
+		kt, err := newKernelTimer()
+		if err != nil {
+			waitableKind = "unavailable"
+			return
+		}
+		if !kt.highRes {
+			waitableKind = "CreateWaitableTimerEx+timeBeginPeriod"
+		}
+		kt.release()
+	})
+	return waitableKind
+}
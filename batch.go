@@ -0,0 +1,87 @@
+package monotime
+
+import "time"
+
+// Batch is a burst of expirations of a BatchTicker's timer, delivered as one
+// value.
+type Batch struct {
+	// Due is the time the latest of the expirations came due.
+	Due Time
+	// Count is the number of expirations in the batch: one, unless the
+	// receiver fell behind or the process was paused.
+	Count uint64
+}
+
+// BatchTicker is a ticker that delivers expirations the receiver fell behind
+// on as one value carrying their count, rather than one tick each as a Ticker
+// does. A slow receiver then sees a single catch-up value and decides for
+// itself how to handle the burst, instead of being handed the ticks back to
+// back.
+type BatchTicker struct {
+	// C receives a Batch of the expirations since the previous one was
+	// received.
+	C <-chan Batch
+
+	c    chan Batch
+	kt   *kernelTimer
+	copy copyCheck
+}
+
+// NewBatchTicker returns a new BatchTicker whose first expiration is d from
+// now, and every d after that. d must be greater than zero; if not,
+// NewBatchTicker will panic. Stop the ticker to release its kernel resources.
+func NewBatchTicker(d time.Duration) *BatchTicker {
+	if d <= 0 {
+		panic("non-positive interval for NewBatchTicker")
+	}
+	kt, err := newKernelTimer()
+	if err != nil {
+		panic(err)
+	}
+	due := Now().Add(d)
+	if err := kt.arm(due, d); err != nil {
+		kt.release()
+		panic(err)
+	}
+
+	// The channel holds one pending batch. Expirations that come due
+	// before it is received are merged into it, so the goroutine never
+	// waits on the receiver.
+	c := make(chan Batch, 1)
+	t := &BatchTicker{C: c, c: c, kt: kt}
+	t.copy.init()
+	kt.start(func(n uint64) bool {
+		due = due.Add(time.Duration(n-1) * d)
+		b := Batch{Due: due, Count: n}
+		due = due.Add(d)
+		// Only this goroutine sends, so once any stale batch is taken
+		// back the send succeeds.
+		select {
+		case old := <-c:
+			b.Count += old.Count
+		default:
+		}
+		c <- b
+		return true
+	})
+	return t
+}
+
+// Err returns a channel that receives the error that ended the ticker, should
+// its kernel timer fail; see Ticker.Err.
+func (t *BatchTicker) Err() <-chan error {
+	return t.kt.errc
+}
+
+// Stop turns off the ticker. Once Stop returns no more batches will be sent,
+// any batch not yet received is discarded, and the ticker's kernel timer is
+// released. Stop does not close the channel. Calling Stop more than once has
+// no further effect.
+func (t *BatchTicker) Stop() {
+	t.copy.check("BatchTicker.Stop")
+	t.kt.stop()
+	select {
+	case <-t.c:
+	default:
+	}
+}
@@ -0,0 +1,40 @@
+package monotime
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBatchTicker(t *testing.T) {
+	const d = time.Millisecond
+	start := Now()
+	ticker := NewBatchTicker(d)
+	defer ticker.Stop()
+
+	first := <-ticker.C
+	if first.Count == 0 || first.Due < start.Add(d) {
+		t.Errorf("first batch %+v, want one due at least %d", first, start.Add(d))
+	}
+
+	// Falling behind makes the next batch carry the missed expirations.
+	time.Sleep(10 * d)
+	b := <-ticker.C
+	if b.Count < 5 {
+		t.Errorf("batch after sleeping 10 periods has count %d", b.Count)
+	}
+	if want := first.Due.Add(time.Duration(b.Count) * d); b.Due != want {
+		t.Errorf("batch due at %d, want %d", b.Due, want)
+	}
+}
+
+func TestBatchTickerStop(t *testing.T) {
+	ticker := NewBatchTicker(time.Millisecond)
+	<-ticker.C
+	ticker.Stop()
+	ticker.Stop()
+	select {
+	case <-ticker.C:
+		t.Fatal("batch after Stop")
+	case <-time.After(10 * time.Millisecond):
+	}
+}
@@ -0,0 +1,19 @@
+package monotime
+
+import "sync"
+
+var (
+	bootIDOnce sync.Once
+	bootIDVal  string
+	bootIDErr  error
+)
+
+// BootID returns the kernel's random identifier for the current boot. Two
+// monotonic times are only comparable if they were taken under the same
+// boot ID.
+func BootID() (string, error) {
+	bootIDOnce.Do(func() {
+		bootIDVal, bootIDErr = readBootID()
+	})
+	return bootIDVal, bootIDErr
+}
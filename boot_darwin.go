@@ -0,0 +1,7 @@
+package monotime
+
+import "golang.org/x/sys/unix"
+
+func readBootID() (string, error) {
+	return unix.Sysctl("kern.bootsessionuuid")
+}
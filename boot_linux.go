@@ -0,0 +1,11 @@
+package monotime
+
+import (
+	"io/ioutil"
+	"strings"
+)
+
+func readBootID() (string, error) {
+	b, err := ioutil.ReadFile("/proc/sys/kernel/random/boot_id")
+	return strings.TrimSpace(string(b)), err
+}
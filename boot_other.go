@@ -0,0 +1,13 @@
+//go:build !linux && !darwin
+// +build !linux,!darwin
+
+package monotime
+
+import (
+	"errors"
+	"runtime"
+)
+
+func readBootID() (string, error) {
+	return "", errors.New("Error reading boot ID: no boot ID on " + runtime.GOOS)
+}
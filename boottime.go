@@ -0,0 +1,19 @@
+//go:build darwin || linux || openbsd || windows
+// +build darwin linux openbsd windows
+
+package monotime
+
+// boottimeClock is the clock NowBoottime reads, which keeps counting while the
+// system is suspended.
+var boottimeClock = kernelClock{newBoottimeTimer, NowBoottime}
+
+func init() {
+	timerClocks[ClockBoottime] = boottimeClock
+}
+
+// NewBoottimeTimerAt is like NewTimerAt, but expires when the clock
+// NowBoottime reads reaches t, so a timeout set before a suspend expires on
+// resume if its time passed while the system slept.
+func NewBoottimeTimerAt(t Time) *Timer {
+	return newTimer(boottimeClock, t, Exact)
+}
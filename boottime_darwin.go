@@ -0,0 +1,26 @@
+package monotime
+
+import (
+	"fmt"
+
+	"golang.org/x/sys/unix"
+)
+
+// NowBoottime returns the current time on the clock that keeps counting while
+// the system sleeps: mach_continuous_time, which Darwin exposes as
+// CLOCK_MONOTONIC_RAW. It is the equivalent of Linux's CLOCK_BOOTTIME, for
+// timeouts and measurements that must include time spent suspended. Its
+// readings share no origin with Now's and must not be mixed with them.
+func NowBoottime() Time {
+	var ts unix.Timespec
+	if err := unix.ClockGettime(unix.CLOCK_MONOTONIC_RAW, &ts); err != nil {
+		err = fmt.Errorf("Error getting continuous time from the kernel: %w", err)
+		panic(err)
+	}
+	return Time(ts.Nano())
+}
+
+// newBoottimeTimer returns a kernelTimer on mach_continuous_time.
+func newBoottimeTimer() (*kernelTimer, error) {
+	return newKqueueTimer(unix.NOTE_MACH_CONTINUOUS_TIME, NowBoottime)
+}
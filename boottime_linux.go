@@ -0,0 +1,16 @@
+package monotime
+
+import "golang.org/x/sys/unix"
+
+// NowBoottime returns the current time on CLOCK_BOOTTIME, which unlike Now's
+// CLOCK_MONOTONIC keeps counting while the system is suspended, for timeouts
+// and measurements that must include time spent asleep. Its readings share no
+// origin with Now's and must not be mixed with them.
+func NowBoottime() Time {
+	return readClock(unix.CLOCK_BOOTTIME)
+}
+
+// newBoottimeTimer returns a kernelTimer on CLOCK_BOOTTIME.
+func newBoottimeTimer() (*kernelTimer, error) {
+	return newTimerfd(unix.CLOCK_BOOTTIME)
+}
@@ -0,0 +1,10 @@
+package monotime
+
+import "testing"
+
+func TestNowBoottimeIncludesMonotonic(t *testing.T) {
+	// CLOCK_BOOTTIME is CLOCK_MONOTONIC plus the time spent suspended.
+	if mono, boot := Now(), NowBoottime(); boot < mono {
+		t.Errorf("NowBoottime %v behind Now %v", boot, mono)
+	}
+}
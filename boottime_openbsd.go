@@ -0,0 +1,37 @@
+package monotime
+
+import (
+	"fmt"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// clockBoottime is OpenBSD's CLOCK_BOOTTIME, which x/sys/unix lacks.
+const clockBoottime = 6
+
+func init() {
+	clockIDs[ClockBoottime] = clockBoottime
+}
+
+// NowBoottime returns the current time on CLOCK_BOOTTIME, which unlike
+// CLOCK_UPTIME keeps counting while the system is suspended, for timeouts and
+// measurements that must include time spent asleep. Its readings share no
+// origin with Now's and must not be mixed with them.
+func NowBoottime() Time {
+	var spec unix.Timespec
+	_, _, errno := unix.Syscall(unix.SYS_CLOCK_GETTIME, clockBoottime, uintptr(unsafe.Pointer(&spec)), 0)
+	if errno != 0 {
+		err := fmt.Errorf("Error getting boottime from the kernel: %w", errno)
+		panic(err)
+	}
+	return Time(spec.Nano())
+}
+
+// newBoottimeTimer returns a kernelTimer scheduled on CLOCK_BOOTTIME. Kqueue
+// timers themselves may not count time suspended, so after a resume the timer
+// can fire up to one wait late; the expirations are still counted on
+// CLOCK_BOOTTIME.
+func newBoottimeTimer() (*kernelTimer, error) {
+	return newKqueueTimer(0, NowBoottime)
+}
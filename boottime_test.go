@@ -0,0 +1,38 @@
+//go:build darwin || linux || openbsd || windows
+// +build darwin linux openbsd windows
+
+package monotime
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBoottimeTicker(t *testing.T) {
+	start := NowBoottime()
+	ticker := NewTicker(time.Millisecond, WithClock(ClockBoottime))
+	defer ticker.Stop()
+	for i := 1; i <= 5; i++ {
+		select {
+		case <-ticker.C:
+		case <-time.After(time.Second):
+			t.Fatal("ticker did not tick")
+		}
+		if d := NowBoottime().Sub(start); d < time.Duration(i)*time.Millisecond {
+			t.Errorf("tick %d after %v, before it was due", i, d)
+		}
+	}
+}
+
+func TestBoottimeTimer(t *testing.T) {
+	deadline := NowBoottime().Add(2 * time.Millisecond)
+	timer := NewBoottimeTimerAt(deadline)
+	select {
+	case <-timer.C:
+	case <-time.After(time.Second):
+		t.Fatal("timer did not fire")
+	}
+	if now := NowBoottime(); now < deadline {
+		t.Errorf("timer fired %v early", deadline.Sub(now))
+	}
+}
@@ -0,0 +1,17 @@
+package monotime
+
+// NowBoottime returns the current time on the performance counter, which
+// unlike Now keeps counting while the system sleeps or hibernates, for
+// timeouts and measurements that must include time spent asleep. Its readings
+// share no origin with Now's and must not be mixed with them.
+func NowBoottime() Time {
+	return readQPC()
+}
+
+// newBoottimeTimer returns a kernelTimer scheduled on the performance counter.
+// Waitable timers themselves may not count time asleep, so after a resume the
+// timer can fire up to one wait late; the expirations are still counted on
+// the performance counter.
+func newBoottimeTimer() (*kernelTimer, error) {
+	return newWaitableTimer(NowBoottime)
+}
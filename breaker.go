@@ -0,0 +1,249 @@
+package monotime
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrBreakerOpen is returned by CircuitBreaker when a call is rejected
+// because the breaker is open, or half-open with all probes in flight.
+var ErrBreakerOpen = errors.New("circuit breaker is open")
+
+// BreakerState is the state of a CircuitBreaker.
+type BreakerState int
+
+const (
+	// BreakerClosed lets every call through while tracking failures.
+	BreakerClosed BreakerState = iota
+	// BreakerOpen rejects every call until the cooldown elapses.
+	BreakerOpen
+	// BreakerHalfOpen lets a limited number of probe calls through to
+	// decide whether to close again.
+	BreakerHalfOpen
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerClosed:
+		return "closed"
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half-open"
+	}
+	return "unknown"
+}
+
+// BreakerConfig configures a CircuitBreaker. Zero fields take the defaults
+// documented on each.
+type BreakerConfig struct {
+	// Window is the span over which the failure rate is measured.
+	// Defaults to 10s.
+	Window time.Duration
+
+	// Buckets is the number of slices Window is divided into; older
+	// slices expire one at a time. Defaults to 10.
+	Buckets int
+
+	// MinCalls is the number of calls that must be seen in Window before
+	// the breaker may trip. Defaults to 20.
+	MinCalls int
+
+	// FailureRatio is the fraction of failed calls in Window at or above
+	// which the breaker trips. Defaults to 0.5.
+	FailureRatio float64
+
+	// Cooldown is how long the breaker stays open before probing.
+	// Defaults to 5s.
+	Cooldown time.Duration
+
+	// Probes is the number of calls let through while half-open. The
+	// breaker closes once that many succeed and reopens on any failure.
+	// Defaults to 1.
+	Probes int
+}
+
+type breakerBucket struct {
+	slot     int64
+	calls    int
+	failures int
+}
+
+// CircuitBreaker stops calling a failing dependency for a while once its
+// failure rate gets too high, then probes it before letting traffic back.
+//
+// Every interval the breaker uses, from the failure-rate window to the open
+// cooldown, is measured on the monotonic clock, so stepping the wall clock
+// can neither trip a healthy breaker nor hold a tripped one open. It is safe
+// for concurrent use.
+type CircuitBreaker struct {
+	cfg   BreakerConfig
+	width time.Duration
+
+	mu         sync.Mutex
+	state      BreakerState
+	generation uint64
+	buckets    []breakerBucket
+	openedAt   Time
+	probing    int
+	probed     int
+}
+
+// NewCircuitBreaker returns a closed CircuitBreaker.
+func NewCircuitBreaker(cfg BreakerConfig) *CircuitBreaker {
+	if cfg.Window <= 0 {
+		cfg.Window = 10 * time.Second
+	}
+	if cfg.Buckets <= 0 {
+		cfg.Buckets = 10
+	}
+	if cfg.MinCalls <= 0 {
+		cfg.MinCalls = 20
+	}
+	if cfg.FailureRatio <= 0 {
+		cfg.FailureRatio = 0.5
+	}
+	if cfg.Cooldown <= 0 {
+		cfg.Cooldown = 5 * time.Second
+	}
+	if cfg.Probes <= 0 {
+		cfg.Probes = 1
+	}
+
+	width := cfg.Window / time.Duration(cfg.Buckets)
+	if width <= 0 {
+		width = 1
+	}
+	return &CircuitBreaker{
+		cfg:     cfg,
+		width:   width,
+		buckets: make([]breakerBucket, cfg.Buckets),
+	}
+}
+
+// State returns the current state of the breaker.
+func (b *CircuitBreaker) State() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.advance(Now())
+	return b.state
+}
+
+// Allow asks whether a call may proceed. If it may, the caller must make the
+// call and then report its outcome through done exactly once. Otherwise Allow
+// returns ErrBreakerOpen.
+func (b *CircuitBreaker) Allow() (done func(success bool), err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.advance(Now())
+	switch b.state {
+	case BreakerOpen:
+		return nil, ErrBreakerOpen
+	case BreakerHalfOpen:
+		if b.probing+b.probed >= b.cfg.Probes {
+			return nil, ErrBreakerOpen
+		}
+		b.probing++
+	}
+
+	generation := b.generation
+	var once sync.Once
+	return func(success bool) {
+		once.Do(func() { b.report(generation, success) })
+	}, nil
+}
+
+// Do runs fn if the breaker allows it and records its outcome; any non-nil
+// error counts as a failure.
+func (b *CircuitBreaker) Do(fn func() error) error {
+	done, err := b.Allow()
+	if err != nil {
+		return err
+	}
+	err = fn()
+	done(err == nil)
+	return err
+}
+
+func (b *CircuitBreaker) report(generation uint64, success bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := Now()
+	b.advance(now)
+	if generation != b.generation {
+		// The breaker changed state since this call was allowed; its
+		// outcome says nothing about the current state.
+		return
+	}
+
+	switch b.state {
+	case BreakerClosed:
+		bucket := b.bucket(now)
+		bucket.calls++
+		if !success {
+			bucket.failures++
+			b.maybeTrip(now)
+		}
+	case BreakerHalfOpen:
+		b.probing--
+		if !success {
+			b.setState(BreakerOpen, now)
+			return
+		}
+		b.probed++
+		if b.probed >= b.cfg.Probes {
+			b.setState(BreakerClosed, now)
+		}
+	}
+}
+
+// advance moves an open breaker to half-open once its cooldown has elapsed.
+func (b *CircuitBreaker) advance(now Time) {
+	if b.state == BreakerOpen && now.Sub(b.openedAt) >= b.cfg.Cooldown {
+		b.setState(BreakerHalfOpen, now)
+	}
+}
+
+func (b *CircuitBreaker) maybeTrip(now Time) {
+	current := int64(now) / int64(b.width)
+	var calls, failures int
+	for _, bucket := range b.buckets {
+		if current-bucket.slot < int64(len(b.buckets)) {
+			calls += bucket.calls
+			failures += bucket.failures
+		}
+	}
+	if calls >= b.cfg.MinCalls && float64(failures) >= b.cfg.FailureRatio*float64(calls) {
+		b.setState(BreakerOpen, now)
+	}
+}
+
+func (b *CircuitBreaker) bucket(now Time) *breakerBucket {
+	slot := int64(now) / int64(b.width)
+	i := slot % int64(len(b.buckets))
+	if i < 0 {
+		i += int64(len(b.buckets))
+	}
+	bucket := &b.buckets[i]
+	if bucket.slot != slot {
+		*bucket = breakerBucket{slot: slot}
+	}
+	return bucket
+}
+
+func (b *CircuitBreaker) setState(state BreakerState, now Time) {
+	b.state = state
+	b.generation++
+	b.probing, b.probed = 0, 0
+	switch state {
+	case BreakerOpen:
+		b.openedAt = now
+	case BreakerClosed:
+		for i := range b.buckets {
+			b.buckets[i] = breakerBucket{}
+		}
+	}
+}
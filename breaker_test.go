@@ -0,0 +1,94 @@
+package monotime
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+var errCall = errors.New("call failed")
+
+func TestCircuitBreakerTransitions(t *testing.T) {
+	const cooldown = 20 * time.Millisecond
+	b := NewCircuitBreaker(BreakerConfig{MinCalls: 4, FailureRatio: 0.5, Cooldown: cooldown, Probes: 2})
+	if s := b.State(); s != BreakerClosed {
+		t.Fatalf("new breaker %v", s)
+	}
+
+	// Below MinCalls, failures don't trip it.
+	b.Do(func() error { return errCall })
+	b.Do(func() error { return nil })
+	b.Do(func() error { return errCall })
+	if s := b.State(); s != BreakerClosed {
+		t.Fatalf("breaker %v after 3 calls", s)
+	}
+	b.Do(func() error { return errCall })
+	if s := b.State(); s != BreakerOpen {
+		t.Fatalf("breaker %v at 3 failures in 4 calls", s)
+	}
+	if err := b.Do(func() error { return nil }); err != ErrBreakerOpen {
+		t.Fatalf("open breaker let a call through: %v", err)
+	}
+
+	// After the cooldown, the probes decide.
+	time.Sleep(cooldown)
+	if s := b.State(); s != BreakerHalfOpen {
+		t.Fatalf("breaker %v after cooldown", s)
+	}
+	done1, err := b.Allow()
+	if err != nil {
+		t.Fatal(err)
+	}
+	done2, err := b.Allow()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.Allow(); err != ErrBreakerOpen {
+		t.Fatalf("half-open breaker let a third probe through: %v", err)
+	}
+	done1(true)
+	done1(false) // only the first report counts
+	if s := b.State(); s != BreakerHalfOpen {
+		t.Fatalf("breaker %v after one of two probes", s)
+	}
+	done2(true)
+	if s := b.State(); s != BreakerClosed {
+		t.Fatalf("breaker %v after successful probes", s)
+	}
+}
+
+func TestCircuitBreakerProbeFailureReopens(t *testing.T) {
+	const cooldown = 10 * time.Millisecond
+	b := NewCircuitBreaker(BreakerConfig{MinCalls: 1, Cooldown: cooldown})
+	b.Do(func() error { return errCall })
+	if s := b.State(); s != BreakerOpen {
+		t.Fatalf("breaker %v after failure", s)
+	}
+	time.Sleep(cooldown)
+	if err := b.Do(func() error { return errCall }); err != errCall {
+		t.Fatalf("probe returned %v", err)
+	}
+	if s := b.State(); s != BreakerOpen {
+		t.Fatalf("breaker %v after failed probe", s)
+	}
+}
+
+func TestCircuitBreakerStaleReport(t *testing.T) {
+	b := NewCircuitBreaker(BreakerConfig{MinCalls: 1, Cooldown: time.Hour})
+	done, _ := b.Allow()
+	b.Do(func() error { return errCall })
+	// The breaker opened since done's call was allowed; its outcome is
+	// ignored.
+	done(true)
+	if s := b.State(); s != BreakerOpen {
+		t.Fatalf("stale report moved breaker to %v", s)
+	}
+}
+
+func TestBreakerStateString(t *testing.T) {
+	for s, want := range map[BreakerState]string{BreakerClosed: "closed", BreakerOpen: "open", BreakerHalfOpen: "half-open", 7: "unknown"} {
+		if got := s.String(); got != want {
+			t.Errorf("%d.String() = %q, want %q", int(s), got, want)
+		}
+	}
+}
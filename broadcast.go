@@ -0,0 +1,181 @@
+package monotime
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// DropPolicy decides what a Broadcaster does with a tick for a subscriber
+// whose channel is full.
+type DropPolicy int
+
+const (
+	// DropNewest discards the new tick, keeping the ones already queued.
+	DropNewest DropPolicy = iota
+	// DropOldest discards the oldest queued tick to make room for the new
+	// one.
+	DropOldest
+	// Block waits for the subscriber to make room. A blocked subscriber
+	// delays delivery to every other subscriber, so use it only for
+	// consumers that must see every tick.
+	Block
+)
+
+// Broadcaster fans the ticks of one Ticker out to many subscribers, so any
+// number of consumers sharing a period cost a single kernel timer and a single
+// wakeup per tick.
+type Broadcaster struct {
+	ticker *Ticker
+	done   chan struct{}
+	exited chan struct{}
+
+	mu   sync.Mutex
+	subs map[*Subscription]struct{}
+}
+
+// Subscription is one consumer of a Broadcaster's ticks.
+type Subscription struct {
+	// C receives the due time of each of the subscriber's ticks.
+	C <-chan Time
+
+	c       chan Time
+	policy  DropPolicy
+	every   uint64
+	seen    uint64 // only touched by the Broadcaster's goroutine
+	quit    chan struct{}
+	b       *Broadcaster
+	dropped uint64 // atomic
+}
+
+// NewBroadcaster starts fanning out the ticks of t. The Broadcaster takes
+// ownership of t: stop it through Broadcaster.Stop, not directly.
+func NewBroadcaster(t *Ticker) *Broadcaster {
+	b := &Broadcaster{
+		ticker: t,
+		done:   make(chan struct{}),
+		exited: make(chan struct{}),
+		subs:   make(map[*Subscription]struct{}),
+	}
+	go b.run()
+	return b
+}
+
+// Subscribe adds a subscriber whose channel holds up to buffer ticks, with
+// policy deciding what happens to ticks that don't fit.
+func (b *Broadcaster) Subscribe(buffer int, policy DropPolicy) *Subscription {
+	return b.SubscribeEvery(1, buffer, policy)
+}
+
+// SubscribeEvery is like Subscribe, but the subscriber only receives every
+// n-th tick, counted from when it subscribes. This lets one fast master
+// Ticker drive consumers at several multiples of its period, e.g. a 10ms
+// master feeding 10ms, 100ms and 1s consumers. n must be greater than zero;
+// if not, SubscribeEvery will panic.
+func (b *Broadcaster) SubscribeEvery(n, buffer int, policy DropPolicy) *Subscription {
+	if n <= 0 {
+		panic("non-positive divider for SubscribeEvery")
+	}
+	if policy == DropOldest && buffer < 1 {
+		// There is nothing to drop from an unbuffered channel.
+		buffer = 1
+	}
+	c := make(chan Time, buffer)
+	s := &Subscription{
+		C:      c,
+		c:      c,
+		policy: policy,
+		every:  uint64(n),
+		quit:   make(chan struct{}),
+		b:      b,
+	}
+	b.mu.Lock()
+	b.subs[s] = struct{}{}
+	b.mu.Unlock()
+	return s
+}
+
+// Stop stops the underlying Ticker and ends delivery to every subscriber.
+func (b *Broadcaster) Stop() {
+	b.ticker.Stop()
+	b.mu.Lock()
+	select {
+	case <-b.done:
+	default:
+		close(b.done)
+	}
+	b.mu.Unlock()
+	<-b.exited
+}
+
+func (b *Broadcaster) run() {
+	defer close(b.exited)
+	var subs []*Subscription
+	for {
+		var due Time
+		select {
+		case due = <-b.ticker.C:
+		case <-b.done:
+			return
+		}
+
+		b.mu.Lock()
+		subs = subs[:0]
+		for s := range b.subs {
+			subs = append(subs, s)
+		}
+		b.mu.Unlock()
+
+		for _, s := range subs {
+			s.seen++
+			if s.seen%s.every == 0 {
+				s.deliver(due, b.done)
+			}
+		}
+	}
+}
+
+// Unsubscribe stops delivery to s. It does not close s.C.
+func (s *Subscription) Unsubscribe() {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if _, ok := s.b.subs[s]; ok {
+		delete(s.b.subs, s)
+		close(s.quit)
+	}
+}
+
+// Dropped returns the number of ticks discarded because s's channel was
+// full.
+func (s *Subscription) Dropped() uint64 {
+	return atomic.LoadUint64(&s.dropped)
+}
+
+func (s *Subscription) deliver(due Time, done <-chan struct{}) {
+	switch s.policy {
+	case Block:
+		select {
+		case s.c <- due:
+		case <-s.quit:
+		case <-done:
+		}
+	case DropOldest:
+		for {
+			select {
+			case s.c <- due:
+				return
+			default:
+			}
+			select {
+			case <-s.c:
+				atomic.AddUint64(&s.dropped, 1)
+			default:
+			}
+		}
+	default:
+		select {
+		case s.c <- due:
+		default:
+			atomic.AddUint64(&s.dropped, 1)
+		}
+	}
+}
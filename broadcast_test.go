@@ -0,0 +1,132 @@
+package monotime
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBroadcaster(t *testing.T) {
+	b := NewBroadcaster(NewTicker(time.Millisecond))
+	defer b.Stop()
+	subs := []*Subscription{b.Subscribe(1, Block), b.Subscribe(1, Block)}
+	// Every subscriber sees the same ticks, in order.
+	var prev Time
+	for i := 0; i < 5; i++ {
+		var due Time
+		for j, s := range subs {
+			select {
+			case got := <-s.C:
+				if j > 0 && got != due {
+					t.Errorf("subscriber %d got tick %v, want %v", j, got, due)
+				}
+				due = got
+			case <-time.After(time.Second):
+				t.Fatalf("subscriber %d missed tick %d", j, i)
+			}
+		}
+		if due <= prev {
+			t.Errorf("tick %d due at %v, not after %v", i, due, prev)
+		}
+		prev = due
+	}
+}
+
+func TestBroadcasterDropNewest(t *testing.T) {
+	b := NewBroadcaster(NewTicker(time.Millisecond))
+	defer b.Stop()
+	s := b.Subscribe(1, DropNewest)
+	first := <-s.C
+	time.Sleep(10 * time.Millisecond)
+	// The tick queued first is kept; later ones are dropped.
+	if got := <-s.C; got != first.Add(time.Millisecond) {
+		t.Errorf("kept tick due at %v, want the one after %v", got, first)
+	}
+	if s.Dropped() == 0 {
+		t.Error("no ticks dropped for a full subscriber")
+	}
+}
+
+func TestBroadcasterDropOldest(t *testing.T) {
+	b := NewBroadcaster(NewTicker(time.Millisecond))
+	defer b.Stop()
+	s := b.Subscribe(0, DropOldest)
+	first := <-s.C
+	time.Sleep(10 * time.Millisecond)
+	// Older ticks make way for newer ones.
+	if got := <-s.C; got.Sub(first) < 5*time.Millisecond {
+		t.Errorf("kept tick due %v after the first, want a recent one", got.Sub(first))
+	}
+	if s.Dropped() == 0 {
+		t.Error("no ticks dropped for a full subscriber")
+	}
+}
+
+func TestBroadcasterUnsubscribe(t *testing.T) {
+	b := NewBroadcaster(NewTicker(time.Millisecond))
+	defer b.Stop()
+	blocked := b.Subscribe(0, Block)
+	other := b.Subscribe(1, DropOldest)
+	// A blocked subscriber that unsubscribes no longer holds up the rest.
+	time.Sleep(5 * time.Millisecond)
+	blocked.Unsubscribe()
+	blocked.Unsubscribe()
+	<-other.C
+	select {
+	case <-other.C:
+	case <-time.After(time.Second):
+		t.Fatal("delivery stalled after Unsubscribe")
+	}
+	select {
+	case <-blocked.C:
+		t.Error("tick delivered after Unsubscribe")
+	case <-time.After(5 * time.Millisecond):
+	}
+}
+
+func TestBroadcasterStop(t *testing.T) {
+	b := NewBroadcaster(NewTicker(time.Millisecond))
+	s := b.Subscribe(0, Block)
+	time.Sleep(5 * time.Millisecond)
+	// Stop ends a delivery blocked on a subscriber that isn't receiving.
+	stopped := make(chan struct{})
+	go func() {
+		b.Stop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Stop hung on a blocked subscriber")
+	}
+	select {
+	case <-s.C:
+		t.Error("tick after Stop")
+	case <-time.After(5 * time.Millisecond):
+	}
+}
+
+func TestBroadcasterSubscribeEvery(t *testing.T) {
+	const d = time.Millisecond
+	b := NewBroadcaster(NewTicker(d))
+	defer b.Stop()
+	every := b.SubscribeEvery(3, 10, Block)
+	prev := <-every.C
+	for i := 0; i < 3; i++ {
+		got := <-every.C
+		if got.Sub(prev) != 3*d {
+			t.Errorf("every third tick %v after the last, want %v", got.Sub(prev), 3*d)
+		}
+		prev = got
+	}
+}
+
+func TestSubscribeEveryPanics(t *testing.T) {
+	b := NewBroadcaster(NewTicker(time.Hour))
+	defer b.Stop()
+	defer func() {
+		if recover() == nil {
+			t.Error("SubscribeEvery(0, ...) did not panic")
+		}
+	}()
+	b.SubscribeEvery(0, 1, Block)
+}
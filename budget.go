@@ -0,0 +1,72 @@
+package monotime
+
+import (
+	"context"
+	"time"
+)
+
+// Budget is the time allotted to an operation, held as a deadline on the
+// monotonic clock. Sub-budgets carved out of a Budget never end after it, so
+// a timeout chosen at the top of a call chain bounds everything beneath it.
+// The zero Budget is already exhausted.
+type Budget struct {
+	deadline Time
+}
+
+// NewBudget returns a Budget of d starting now.
+func NewBudget(d time.Duration) Budget {
+	return Budget{deadline: Now().AddSat(d)}
+}
+
+// BudgetUntil returns a Budget that ends at deadline.
+func BudgetUntil(deadline Time) Budget {
+	return Budget{deadline: deadline}
+}
+
+// Deadline returns the time the budget ends.
+func (b Budget) Deadline() Time {
+	return b.deadline
+}
+
+// Remaining returns the time left in the budget, or zero once it has run out.
+func (b Budget) Remaining() time.Duration {
+	r := b.deadline.SubSat(Now())
+	if r < 0 {
+		return 0
+	}
+	return r
+}
+
+// Exhausted reports whether the budget has run out.
+func (b Budget) Exhausted() bool {
+	return b.Remaining() == 0
+}
+
+// Reserve returns a sub-budget that ends d before b does, holding d back for
+// work that must happen afterwards, such as cleanup or a fallback. If less
+// than d remains, the sub-budget is already exhausted.
+func (b Budget) Reserve(d time.Duration) Budget {
+	return Budget{deadline: b.deadline.AddSat(-d)}
+}
+
+// Split returns a sub-budget for the next of n remaining steps: an equal share
+// of the time left, so later steps are not starved by an earlier one running
+// long. n <= 1 returns b unchanged.
+func (b Budget) Split(n int) Budget {
+	if n <= 1 {
+		return b
+	}
+	return Budget{deadline: Now().Add(b.Remaining() / time.Duration(n))}
+}
+
+// Limit returns a sub-budget of at most d from now, capped by b.
+func (b Budget) Limit(d time.Duration) Budget {
+	return Budget{deadline: Min(Now().AddSat(d), b.deadline)}
+}
+
+// Context returns a copy of parent that is cancelled when the budget runs
+// out. The deadline is converted to wall time only here, through time.Now,
+// whose monotonic reading the context uses to time itself.
+func (b Budget) Context(parent context.Context) (context.Context, context.CancelFunc) {
+	return context.WithDeadline(parent, time.Now().Add(b.deadline.SubSat(Now())))
+}
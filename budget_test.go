@@ -0,0 +1,70 @@
+package monotime
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestBudget(t *testing.T) {
+	b := NewBudget(time.Hour)
+	if r := b.Remaining(); r <= 59*time.Minute || r > time.Hour {
+		t.Errorf("Remaining() = %v, want about 1h", r)
+	}
+	if b.Exhausted() {
+		t.Error("new budget exhausted")
+	}
+	if got := b.Reserve(10 * time.Minute).Deadline(); got != b.Deadline().Add(-10*time.Minute) {
+		t.Errorf("Reserve ends at %v, want 10m before %v", got, b.Deadline())
+	}
+	if !b.Reserve(2 * time.Hour).Exhausted() {
+		t.Error("reserving more than remains left time")
+	}
+	if got := b.Limit(time.Minute).Remaining(); got > time.Minute || got < 59*time.Second {
+		t.Errorf("Limit(1m) leaves %v", got)
+	}
+	if got := b.Limit(2 * time.Hour).Deadline(); got != b.Deadline() {
+		t.Error("Limit extended the budget")
+	}
+	if got := b.Split(4).Remaining(); got > 15*time.Minute || got < 14*time.Minute {
+		t.Errorf("Split(4) leaves %v, want about 15m", got)
+	}
+	if got := b.Split(1); got != b {
+		t.Error("Split(1) changed the budget")
+	}
+
+	var zero Budget
+	if !zero.Exhausted() {
+		t.Error("zero budget not exhausted")
+	}
+	if got := BudgetUntil(42).Deadline(); got != 42 {
+		t.Errorf("BudgetUntil(42).Deadline() = %v", got)
+	}
+}
+
+func TestBudgetSaturates(t *testing.T) {
+	forever := NewBudget(math.MaxInt64)
+	if forever.Exhausted() || forever.Deadline() != math.MaxInt64 {
+		t.Errorf("endless budget ends at %d", forever.Deadline())
+	}
+	if sub := forever.Limit(math.MaxInt64); sub.Deadline() != math.MaxInt64 {
+		t.Errorf("endless sub-budget ends at %d", sub.Deadline())
+	}
+	if r := BudgetUntil(math.MinInt64 + 1).Reserve(time.Hour); !r.Exhausted() || r.Deadline() != math.MinInt64 {
+		t.Errorf("reserving from a spent budget ends at %d", r.Deadline())
+	}
+}
+
+func TestBudgetContext(t *testing.T) {
+	ctx, cancel := NewBudget(time.Millisecond).Context(context.Background())
+	defer cancel()
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("context outlived its budget")
+	}
+	if ctx.Err() != context.DeadlineExceeded {
+		t.Errorf("ctx.Err() = %v", ctx.Err())
+	}
+}
@@ -0,0 +1,129 @@
+package monotime
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// ErrTimeBudgetExceeded and ErrCPUBudgetExceeded report which of a
+// Budgeter's limits ran out.
+var (
+	ErrTimeBudgetExceeded = errors.New("time budget exceeded")
+	ErrCPUBudgetExceeded  = errors.New("CPU budget exceeded")
+)
+
+// CPUScope selects whose CPU time a Budgeter charges.
+type CPUScope int
+
+const (
+	// ProcessCPU charges the CPU time of every thread in the process.
+	ProcessCPU CPUScope = iota
+	// ThreadCPU charges only the CPU time of the thread that created the
+	// Budgeter, which must be locked with runtime.LockOSThread for as long
+	// as the operation runs.
+	ThreadCPU
+)
+
+// Budgeter enforces an elapsed time budget and a CPU time budget on one
+// operation at once, so a request that spins without blocking is cut off
+// however generous its deadline. It is safe for concurrent use.
+type Budgeter struct {
+	start    Time
+	time     Budget
+	cpu      time.Duration
+	cpuNow   func() Time
+	parallel bool // whether CPU time can be spent on several CPUs at once
+	cpuStart Time
+}
+
+// NewBudgeter returns a Budgeter that allows d of elapsed time and cpu of CPU
+// time, both starting now, with CPU time charged according to scope.
+func NewBudgeter(d, cpu time.Duration, scope CPUScope) *Budgeter {
+	cpuNow := NowProcessCPU
+	if scope == ThreadCPU {
+		clockid := threadCPUClock(unix.Gettid())
+		cpuNow = func() Time { return readClock(clockid) }
+	}
+	b := NewBudgeterOn(d, cpu, cpuNow)
+	b.parallel = scope == ProcessCPU
+	return b
+}
+
+// NewBudgeterOn is like NewBudgeter, but charges CPU time as measured by
+// cpuNow, such as the Now method of a CgroupCPU.
+func NewBudgeterOn(d, cpu time.Duration, cpuNow func() Time) *Budgeter {
+	start := Now()
+	return &Budgeter{
+		start:    start,
+		time:     BudgetUntil(start.Add(d)),
+		cpu:      cpu,
+		cpuNow:   cpuNow,
+		parallel: true,
+		cpuStart: cpuNow(),
+	}
+}
+
+// Elapsed returns the time since the Budgeter was created.
+func (b *Budgeter) Elapsed() time.Duration {
+	return Since(b.start)
+}
+
+// CPU returns the CPU time charged since the Budgeter was created.
+func (b *Budgeter) CPU() time.Duration {
+	return b.cpuNow().Sub(b.cpuStart)
+}
+
+// Err returns ErrTimeBudgetExceeded or ErrCPUBudgetExceeded once the
+// corresponding budget has run out, and nil before then.
+func (b *Budgeter) Err() error {
+	if b.time.Exhausted() {
+		return ErrTimeBudgetExceeded
+	}
+	if b.CPU() >= b.cpu {
+		return ErrCPUBudgetExceeded
+	}
+	return nil
+}
+
+// Exceeded reports whether either budget has run out.
+func (b *Budgeter) Exceeded() bool {
+	return b.Err() != nil
+}
+
+// Context returns a copy of parent that is cancelled when either budget runs
+// out; Err tells which. CPU time is checked on no fixed schedule but as often
+// as the remaining CPU budget could have been spent, so the context is
+// cancelled promptly without polling a clock in a tight loop.
+func (b *Budgeter) Context(parent context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := b.time.Context(parent)
+	go func() {
+		for {
+			left := b.cpu - b.CPU()
+			if left <= 0 {
+				cancel()
+				return
+			}
+			// A process can burn CPU time on every CPU at once,
+			// a thread only on one.
+			if b.parallel {
+				left /= time.Duration(runtime.NumCPU())
+			}
+			if left < time.Millisecond {
+				left = time.Millisecond
+			}
+			timer := NewTimerAt(Now().Add(left))
+			select {
+			case <-timer.C:
+				timer.Stop()
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			}
+		}
+	}()
+	return ctx, cancel
+}
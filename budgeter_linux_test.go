@@ -0,0 +1,80 @@
+package monotime
+
+import (
+	"context"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeCPU is a CPU clock the test advances by hand.
+type fakeCPU struct{ t int64 }
+
+func (c *fakeCPU) now() Time             { return Time(atomic.LoadInt64(&c.t)) }
+func (c *fakeCPU) spend(d time.Duration) { atomic.AddInt64(&c.t, int64(d)) }
+func (c *fakeCPU) budgeter(d, cpu time.Duration) *Budgeter {
+	return NewBudgeterOn(d, cpu, c.now)
+}
+
+func TestBudgeter(t *testing.T) {
+	var cpu fakeCPU
+	b := cpu.budgeter(time.Hour, 10*time.Millisecond)
+	if err := b.Err(); err != nil {
+		t.Fatalf("fresh Budgeter: %v", err)
+	}
+	cpu.spend(4 * time.Millisecond)
+	if got := b.CPU(); got != 4*time.Millisecond {
+		t.Errorf("CPU() = %v, want 4ms", got)
+	}
+	cpu.spend(6 * time.Millisecond)
+	if err := b.Err(); err != ErrCPUBudgetExceeded || !b.Exceeded() {
+		t.Errorf("Err() = %v with the CPU budget spent, want ErrCPUBudgetExceeded", err)
+	}
+
+	b = cpu.budgeter(time.Millisecond, time.Hour)
+	time.Sleep(2 * time.Millisecond)
+	if err := b.Err(); err != ErrTimeBudgetExceeded {
+		t.Errorf("Err() = %v with the time budget spent, want ErrTimeBudgetExceeded", err)
+	}
+	if b.Elapsed() < time.Millisecond {
+		t.Errorf("Elapsed() = %v after sleeping 2ms", b.Elapsed())
+	}
+}
+
+func TestBudgeterContext(t *testing.T) {
+	var cpu fakeCPU
+	b := cpu.budgeter(time.Hour, 20*time.Millisecond)
+	ctx, cancel := b.Context(context.Background())
+	defer cancel()
+	select {
+	case <-ctx.Done():
+		t.Fatal("context cancelled with budget left")
+	case <-time.After(10 * time.Millisecond):
+	}
+	cpu.spend(20 * time.Millisecond)
+	select {
+	case <-ctx.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("context not cancelled once the CPU budget was spent")
+	}
+	if err := b.Err(); err != ErrCPUBudgetExceeded {
+		t.Errorf("Err() = %v, want ErrCPUBudgetExceeded", err)
+	}
+}
+
+func TestBudgeterThreadCPU(t *testing.T) {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	b := NewBudgeter(time.Hour, 5*time.Millisecond, ThreadCPU)
+	// Spinning spends the thread's CPU time as fast as elapsed time.
+	deadline := Now().Add(5 * time.Second)
+	for !b.Exceeded() {
+		if Now().After(deadline) {
+			t.Fatalf("spun 5s charging only %v of CPU time", b.CPU())
+		}
+	}
+	if err := b.Err(); err != ErrCPUBudgetExceeded {
+		t.Errorf("Err() = %v, want ErrCPUBudgetExceeded", err)
+	}
+}
@@ -0,0 +1,78 @@
+package monotime
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"golang.org/x/sys/unix"
+)
+
+// CapabilityError reports that an operation needs a Linux capability the
+// process does not hold. It unwraps to unix.EPERM, so errors.Is(err,
+// os.ErrPermission) holds.
+type CapabilityError struct {
+	// Op is the operation that was refused, e.g. "create wake alarm timer".
+	Op string
+	// Capability is the name of the missing capability, e.g. "CAP_WAKE_ALARM".
+	Capability string
+}
+
+func (e *CapabilityError) Error() string {
+	return fmt.Sprintf("%s requires %s; grant it with `setcap %s+ep <binary>` or run as a user that holds it",
+		e.Op, e.Capability, strings.ToLower(e.Capability))
+}
+
+func (e *CapabilityError) Unwrap() error {
+	return unix.EPERM
+}
+
+// CheckWakeAlarm reports whether the process may create timers on the
+// alarm clocks (CLOCK_REALTIME_ALARM, CLOCK_BOOTTIME_ALARM), which can wake
+// the system from suspend. It returns a *CapabilityError if CAP_WAKE_ALARM is
+// missing, so callers can fail early with an actionable message rather than
+// with a bare EPERM from the kernel.
+func CheckWakeAlarm() error {
+	ok, err := hasCapability(unix.CAP_WAKE_ALARM)
+	if err != nil {
+		return fmt.Errorf("Error checking for CAP_WAKE_ALARM: %w", err)
+	}
+	if !ok {
+		return &CapabilityError{Op: "create wake alarm timer", Capability: "CAP_WAKE_ALARM"}
+	}
+	return nil
+}
+
+// hasCapability reports whether capability c is in the effective set of the
+// calling thread, as listed in /proc/thread-self/status.
+func hasCapability(c int) (bool, error) {
+	f, err := os.Open("/proc/thread-self/status")
+	if os.IsNotExist(err) {
+		// Kernels before 3.17 lack thread-self; capabilities are all
+		// but always shared by the process's threads anyway.
+		f, err = os.Open("/proc/self/status")
+	}
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		line := s.Text()
+		if !strings.HasPrefix(line, "CapEff:") {
+			continue
+		}
+		mask, err := strconv.ParseUint(strings.TrimSpace(strings.TrimPrefix(line, "CapEff:")), 16, 64)
+		if err != nil {
+			return false, err
+		}
+		return mask&(1<<uint(c)) != 0, nil
+	}
+	if err := s.Err(); err != nil {
+		return false, err
+	}
+	return false, fmt.Errorf("no CapEff line in %s", f.Name())
+}
@@ -0,0 +1,37 @@
+package monotime
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"golang.org/x/sys/unix"
+)
+
+func TestCapabilityError(t *testing.T) {
+	err := error(&CapabilityError{Op: "create wake alarm timer", Capability: "CAP_WAKE_ALARM"})
+	if !errors.Is(err, os.ErrPermission) {
+		t.Error("CapabilityError is not os.ErrPermission")
+	}
+	if want := "create wake alarm timer requires CAP_WAKE_ALARM; grant it with `setcap cap_wake_alarm+ep <binary>` or run as a user that holds it"; err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+}
+
+func TestAlarmTimerCapability(t *testing.T) {
+	kt, err := newTimerfd(unix.CLOCK_BOOTTIME_ALARM)
+	if CheckWakeAlarm() != nil {
+		var capErr *CapabilityError
+		if !errors.As(err, &capErr) || capErr.Capability != "CAP_WAKE_ALARM" {
+			t.Fatalf("alarm timer without CAP_WAKE_ALARM: err = %v, want a *CapabilityError", err)
+		}
+		return
+	}
+	if errors.Is(err, unix.EINVAL) {
+		t.Skip("no alarm timers on this host:", err)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	kt.release()
+}
@@ -0,0 +1,156 @@
+package monotime
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cgroupRoot is where the cgroup filesystems are mounted.
+const cgroupRoot = "/sys/fs/cgroup"
+
+// CgroupCPU is a clock reading the CPU time used by every process in the
+// calling process's cgroup: the container's CPU time, when running in one.
+// Its Now method can drive a Stopwatch or a Budgeter for per-container
+// accounting. It understands both cgroup v2 (cpu.stat) and v1 (cpuacct).
+type CgroupCPU struct {
+	usage string // the file holding the usage
+	stat  string // cpu.stat, holding throttling statistics
+	v2    bool
+}
+
+// OpenCgroupCPU finds the CPU accounting files of the calling process's
+// cgroup.
+func OpenCgroupCPU() (*CgroupCPU, error) {
+	paths, err := selfCgroups()
+	if err != nil {
+		return nil, fmt.Errorf("Error reading cgroup membership: %w", err)
+	}
+
+	if p, ok := paths[""]; ok {
+		if dir, ok := cgroupDir(cgroupRoot, p, "cpu.stat"); ok {
+			stat := filepath.Join(dir, "cpu.stat")
+			return &CgroupCPU{usage: stat, stat: stat, v2: true}, nil
+		}
+	}
+	if p, ok := paths["cpuacct"]; ok {
+		if dir, ok := cgroupDir(filepath.Join(cgroupRoot, "cpuacct"), p, "cpuacct.usage"); ok {
+			c := &CgroupCPU{usage: filepath.Join(dir, "cpuacct.usage")}
+			if dir, ok := cgroupDir(filepath.Join(cgroupRoot, "cpu"), paths["cpu"], "cpu.stat"); ok {
+				c.stat = filepath.Join(dir, "cpu.stat")
+			}
+			return c, nil
+		}
+	}
+	return nil, fmt.Errorf("Error finding cgroup CPU accounting: no cpu.stat or cpuacct.usage under %s", cgroupRoot)
+}
+
+// Now returns the cgroup's total CPU time as a Time, so that readings can be
+// subtracted like monotonic ones.
+func (c *CgroupCPU) Now() Time {
+	var ns int64
+	var err error
+	if c.v2 {
+		var usec int64
+		usec, err = statField(c.usage, "usage_usec")
+		ns = usec * 1000
+	} else {
+		ns, err = readInt(c.usage)
+	}
+	if err != nil {
+		err = fmt.Errorf("Error reading cgroup CPU usage: %w", err)
+		panic(err)
+	}
+	return Time(ns)
+}
+
+// Throttled returns the total time the cgroup's processes have been held
+// back by its CPU quota. Timings that include throttling are slow because of
+// the quota, not because of the work.
+func (c *CgroupCPU) Throttled() (time.Duration, error) {
+	if c.stat == "" {
+		return 0, fmt.Errorf("Error reading cgroup throttling: no cpu controller")
+	}
+	if c.v2 {
+		usec, err := statField(c.stat, "throttled_usec")
+		if err != nil {
+			return 0, fmt.Errorf("Error reading cgroup throttling: %w", err)
+		}
+		return time.Duration(usec) * time.Microsecond, nil
+	}
+	ns, err := statField(c.stat, "throttled_time")
+	if err != nil {
+		return 0, fmt.Errorf("Error reading cgroup throttling: %w", err)
+	}
+	return time.Duration(ns), nil
+}
+
+// selfCgroups maps each of the calling process's cgroup v1 controllers to its
+// cgroup path, with the cgroup v2 path under "".
+func selfCgroups() (map[string]string, error) {
+	f, err := os.Open("/proc/self/cgroup")
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	paths := make(map[string]string)
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		// hierarchy-ID:controller-list:cgroup-path
+		parts := strings.SplitN(s.Text(), ":", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		if parts[0] == "0" && parts[1] == "" {
+			paths[""] = parts[2]
+			continue
+		}
+		for _, ctrl := range strings.Split(parts[1], ",") {
+			paths[ctrl] = parts[2]
+		}
+	}
+	return paths, s.Err()
+}
+
+// cgroupDir returns the directory of the cgroup at path in the hierarchy
+// mounted at mount, if it holds file. Inside a container the hierarchy is
+// often mounted from the container's own cgroup, so that path, which is
+// relative to the host's root, must be tried against the mount itself too.
+func cgroupDir(mount, path, file string) (string, bool) {
+	for _, dir := range []string{filepath.Join(mount, path), mount} {
+		if _, err := os.Stat(filepath.Join(dir, file)); err == nil {
+			return dir, true
+		}
+	}
+	return "", false
+}
+
+// statField returns the value of the named field in a flat keyed file such
+// as cpu.stat.
+func statField(path, name string) (int64, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	for _, line := range strings.Split(string(b), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 && fields[0] == name {
+			return strconv.ParseInt(fields[1], 10, 64)
+		}
+	}
+	return 0, fmt.Errorf("no %s in %s", name, path)
+}
+
+func readInt(path string) (int64, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(strings.TrimSpace(string(b)), 10, 64)
+}
@@ -0,0 +1,72 @@
+package monotime
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestCgroupCPU(t *testing.T) {
+	c, err := OpenCgroupCPU()
+	if err != nil {
+		t.Skip("no cgroup CPU accounting:", err)
+	}
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	// Spinning spends CPU time the cgroup accounts for; cgroup v2 counts
+	// it in microseconds and may lag a little, so spin well past that.
+	start := c.Now()
+	SpinUntil(Now().Add(20 * time.Millisecond))
+	if used := c.Now().Sub(start); used <= 0 {
+		t.Errorf("cgroup charged %v for 20ms of spinning", used)
+	}
+	if _, err := c.Throttled(); err != nil && c.stat != "" {
+		t.Error(err)
+	}
+}
+
+func TestCgroupFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cgroup")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	stat := filepath.Join(dir, "cpu.stat")
+	if err := ioutil.WriteFile(stat, []byte("usage_usec 1500\nthrottled_usec 20\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	usage := filepath.Join(dir, "cpuacct.usage")
+	if err := ioutil.WriteFile(usage, []byte("123456\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	v2 := &CgroupCPU{usage: stat, stat: stat, v2: true}
+	if got := v2.Now(); got != Time(1500*time.Microsecond) {
+		t.Errorf("cgroup v2 usage %d, want 1.5ms", got)
+	}
+	if got, err := v2.Throttled(); err != nil || got != 20*time.Microsecond {
+		t.Errorf("cgroup v2 throttling %v, %v; want 20µs", got, err)
+	}
+	v1 := &CgroupCPU{usage: usage}
+	if got := v1.Now(); got != 123456 {
+		t.Errorf("cgroup v1 usage %d, want 123456", got)
+	}
+	if _, err := v1.Throttled(); err == nil {
+		t.Error("no error reading throttling without a cpu controller")
+	}
+
+	// The cgroup's own path is tried under the mount, then the mount
+	// itself, as inside a container.
+	if got, ok := cgroupDir(dir, "/not/here", "cpu.stat"); !ok || got != dir {
+		t.Errorf("cgroupDir fell back to %q, %v; want the mount %q", got, ok, dir)
+	}
+	if _, ok := cgroupDir(dir, "/", "memory.stat"); ok {
+		t.Error("cgroupDir found a file that isn't there")
+	}
+	if _, err := statField(stat, "nr_periods"); err == nil {
+		t.Error("no error for a missing cpu.stat field")
+	}
+}
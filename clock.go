@@ -0,0 +1,90 @@
+package monotime
+
+import (
+	"fmt"
+	"runtime"
+	"time"
+)
+
+// kernelClock is a clock that kernel timers can run on.
+type kernelClock struct {
+	newTimer func() (*kernelTimer, error)
+	now      func() Time
+}
+
+// monotonicClock is the clock Now reads, which stops while the system is
+// suspended.
+var monotonicClock = kernelClock{newKernelTimer, Now}
+
+// timerClocks maps each Clock that has kernel timers on this host to them.
+var timerClocks = map[Clock]kernelClock{
+	ClockMonotonic: monotonicClock,
+}
+
+// Clock names one of the clocks the package reads.
+type Clock int
+
+const (
+	// ClockMonotonic is the clock Now reads.
+	ClockMonotonic Clock = iota
+	// ClockRaw is the clock NowRaw reads.
+	ClockRaw
+	// ClockCoarse is the clock NowCoarse reads.
+	ClockCoarse
+	// ClockBoottime is the clock NowBoottime reads.
+	ClockBoottime
+	// ClockTAI is the clock NowTAI reads, on Linux.
+	ClockTAI
+	// ClockProcessCPU is the clock NowProcessCPU reads.
+	ClockProcessCPU
+	// ClockBoottimeAlarm is ClockBoottime, on Linux, whose timers also wake
+	// the system from suspend. Creating them needs CAP_WAKE_ALARM; without
+	// it they fail with a *CapabilityError.
+	ClockBoottimeAlarm
+)
+
+func (c Clock) String() string {
+	switch c {
+	case ClockMonotonic:
+		return "monotonic"
+	case ClockRaw:
+		return "raw"
+	case ClockCoarse:
+		return "coarse"
+	case ClockBoottime:
+		return "boottime"
+	case ClockTAI:
+		return "tai"
+	case ClockProcessCPU:
+		return "process-cpu"
+	case ClockBoottimeAlarm:
+		return "boottime-alarm"
+	}
+	return "unknown"
+}
+
+// errNoClock is the error Resolution returns for a clock the host lacks.
+func errNoClock(c Clock) error {
+	return fmt.Errorf("Error getting resolution of the %v clock: no such clock on %s", c, runtime.GOOS)
+}
+
+// measureResolution estimates the resolution of a clock that advertises none
+// as the smallest step between successive readings.
+func measureResolution(read func() Time) time.Duration {
+	var res time.Duration
+	prev := read()
+	for i := 0; i < 1000; i++ {
+		t := read()
+		if d := t.Sub(prev); d > 0 && (res == 0 || d < res) {
+			res = d
+		}
+		prev = t
+	}
+	return res
+}
+
+// fail records err as the reason a kernelTimer's goroutine is exiting, for
+// Ticker.Err. The goroutine fails at most once, so the send never blocks.
+func (f *kernelTimer) fail(err error) {
+	f.errc <- err
+}
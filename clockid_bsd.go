@@ -0,0 +1,8 @@
+//go:build dragonfly || freebsd
+// +build dragonfly freebsd
+
+package monotime
+
+import "golang.org/x/sys/unix"
+
+const clockMonotonic = unix.CLOCK_MONOTONIC
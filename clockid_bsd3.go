@@ -0,0 +1,8 @@
+//go:build netbsd || openbsd
+// +build netbsd openbsd
+
+package monotime
+
+// clockMonotonic is CLOCK_MONOTONIC on NetBSD and OpenBSD, which x/sys/unix
+// lacks for some of their architectures.
+const clockMonotonic = 3
@@ -0,0 +1,96 @@
+// Command monoload generates load at a precise target rate and reports the
+// latency distribution.
+//
+// With -url it issues HTTP GET requests; otherwise each operation busy-waits
+// for -work, which is useful for checking how precisely the host can hold a
+// schedule.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"os/signal"
+	"text/tabwriter"
+	"time"
+
+	"github.com/thisguycodes/monotime"
+	"github.com/thisguycodes/monotime/loadgen"
+)
+
+func main() {
+	rate := flag.Float64("rate", 100, "operations started per second")
+	duration := flag.Duration("duration", 10*time.Second, "how long to generate load for")
+	concurrency := flag.Int("concurrency", 0, "maximum operations in flight (0 is unbounded)")
+	url := flag.String("url", "", "issue HTTP GET requests to this URL")
+	work := flag.Duration("work", 0, "busy-wait this long per operation when -url is not set")
+	flag.Parse()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt)
+	go func() {
+		<-sig
+		cancel()
+	}()
+
+	op := func(context.Context) error {
+		deadline := monotime.Now().Add(*work)
+		for monotime.Now().Sub(deadline) < 0 {
+		}
+		return nil
+	}
+	if *url != "" {
+		op = func(ctx context.Context) error {
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, *url, nil)
+			if err != nil {
+				return err
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				return err
+			}
+			defer resp.Body.Close()
+			_, err = io.Copy(ioutil.Discard, resp.Body)
+			if err == nil && resp.StatusCode >= 500 {
+				err = fmt.Errorf("%s", resp.Status)
+			}
+			return err
+		}
+	}
+
+	cfg := loadgen.Config{
+		Rate:        *rate,
+		Duration:    *duration,
+		Concurrency: *concurrency,
+	}
+	res, err := loadgen.Run(ctx, cfg, op)
+	if res == nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	fmt.Printf("started %d, failed %d, in %s (%.1f/s)\n",
+		res.Started, res.Failed, monotime.FormatDuration(res.Elapsed), float64(res.Started)/res.Elapsed.Seconds())
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "\tmin\tp50\tp90\tp99\tp99.9\tmax\t")
+	row(w, "latency", res.Latency)
+	row(w, "service", res.Service)
+	row(w, "lag", res.Lag)
+	w.Flush()
+
+	if err != nil {
+		os.Exit(1)
+	}
+}
+
+func row(w io.Writer, name string, h *monotime.Histogram) {
+	fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t\n", name,
+		monotime.FormatFixed(h.Min()), monotime.FormatFixed(h.Quantile(0.5)), monotime.FormatFixed(h.Quantile(0.9)),
+		monotime.FormatFixed(h.Quantile(0.99)), monotime.FormatFixed(h.Quantile(0.999)), monotime.FormatFixed(h.Max()))
+}
@@ -0,0 +1,159 @@
+//go:build linux
+// +build linux
+
+// Command monotime prints diagnostic information about the clocks and timers
+// available on this host: the current reading and resolution of every
+// supported clock, the offset between the monotonic and realtime clocks, the
+// time spent in suspend, and the results of a quick timer-latency self-test.
+//
+// It is meant to be attached to "timers behave weirdly on this host" reports.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/thisguycodes/monotime"
+	"golang.org/x/sys/unix"
+)
+
+type clock struct {
+	name string
+	id   int32
+}
+
+var clocks = []clock{
+	{"CLOCK_MONOTONIC", unix.CLOCK_MONOTONIC},
+	{"CLOCK_MONOTONIC_RAW", unix.CLOCK_MONOTONIC_RAW},
+	{"CLOCK_MONOTONIC_COARSE", unix.CLOCK_MONOTONIC_COARSE},
+	{"CLOCK_BOOTTIME", unix.CLOCK_BOOTTIME},
+	{"CLOCK_REALTIME", unix.CLOCK_REALTIME},
+	{"CLOCK_REALTIME_COARSE", unix.CLOCK_REALTIME_COARSE},
+	{"CLOCK_TAI", unix.CLOCK_TAI},
+	{"CLOCK_PROCESS_CPUTIME_ID", unix.CLOCK_PROCESS_CPUTIME_ID},
+	{"CLOCK_THREAD_CPUTIME_ID", unix.CLOCK_THREAD_CPUTIME_ID},
+}
+
+func main() {
+	samples := flag.Int("samples", 200, "number of sleeps to run in the timer-latency self-test (0 disables it)")
+	interval := flag.Duration("interval", time.Millisecond, "sleep duration used by the timer-latency self-test")
+	flag.Parse()
+
+	printClocks()
+	fmt.Println()
+	printOffsets()
+	fmt.Println()
+	printHost()
+
+	if *samples > 0 {
+		fmt.Println()
+		selfTest(*samples, *interval)
+	}
+}
+
+func printClocks() {
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "CLOCK\tNOW\tRESOLUTION")
+	for _, c := range clocks {
+		now, err := read(c.id)
+		if err != nil {
+			fmt.Fprintf(w, "%s\tunavailable (%v)\t\n", c.name, err)
+			continue
+		}
+		res := "unknown"
+		var ts unix.Timespec
+		if err := unix.ClockGetres(c.id, &ts); err == nil {
+			res = time.Duration(ts.Nano()).String()
+		}
+		fmt.Fprintf(w, "%s\t%d.%09ds\t%s\n", c.name, now/1e9, now%1e9, res)
+	}
+	w.Flush()
+}
+
+func printOffsets() {
+	sample := monotime.SampleClocks()
+	mono := monotime.Now()
+	boot, errBoot := read(unix.CLOCK_BOOTTIME)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	off := time.Duration(sample.Realtime.UnixNano() - int64(sample.Monotonic))
+	fmt.Fprintf(w, "realtime - monotonic\t%s\t(monotonic zero at %s)\n", off, time.Unix(0, int64(off)).Format(time.RFC3339Nano))
+	fmt.Fprintf(w, "monotonic - raw\t%s\t\n", time.Duration(int64(sample.Monotonic)-int64(sample.Raw)))
+	fmt.Fprintf(w, "sample spread\t%s\t\n", sample.Spread)
+	if errBoot == nil {
+		fmt.Fprintf(w, "suspend time (boottime - monotonic)\t%s\t\n", time.Duration(boot-int64(mono)))
+	}
+	w.Flush()
+}
+
+func printHost() {
+	caps := monotime.Capabilities()
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(w, "backend\t%s\n", monotime.Backend())
+	fmt.Fprintf(w, "timer mechanisms\ttimerfd=%t io_uring=%t kqueue=%t\n", caps.Timerfd, caps.IOUring, caps.Kqueue)
+	fmt.Fprintf(w, "fast paths\tvdso=%t tsc=%t\n", caps.VDSO, caps.TSC)
+	fmt.Fprintf(w, "clocksource\t%s\n", sysfs("/sys/devices/system/clocksource/clocksource0/current_clocksource"))
+	fmt.Fprintf(w, "available clocksources\t%s\n", sysfs("/sys/devices/system/clocksource/clocksource0/available_clocksource"))
+	if slack, err := unix.PrctlRetInt(unix.PR_GET_TIMERSLACK, 0, 0, 0, 0); err == nil {
+		fmt.Fprintf(w, "timer slack\t%s\n", time.Duration(slack))
+	}
+	if err := monotime.CheckWakeAlarm(); err != nil {
+		fmt.Fprintf(w, "wake alarms\tunavailable: %v\n", err)
+	} else {
+		fmt.Fprintf(w, "wake alarms\tavailable\n")
+	}
+	w.Flush()
+}
+
+// selfTest measures how late the kernel wakes a thread sleeping on
+// CLOCK_MONOTONIC for interval, which is the floor on how accurately any
+// timer on this host can fire.
+func selfTest(samples int, interval time.Duration) {
+	late := make([]time.Duration, 0, samples)
+	req := unix.NsecToTimespec(int64(interval))
+	for i := 0; i < samples; i++ {
+		start := monotime.Now()
+		if err := unix.ClockNanosleep(unix.CLOCK_MONOTONIC, 0, &req, nil); err != nil {
+			fmt.Fprintf(os.Stderr, "self-test: clock_nanosleep: %v\n", err)
+			return
+		}
+		late = append(late, monotime.Since(start)-interval)
+	}
+	sort.Slice(late, func(i, j int) bool { return late[i] < late[j] })
+
+	var sum time.Duration
+	for _, l := range late {
+		sum += l
+	}
+
+	fmt.Printf("timer latency self-test: %d sleeps of %s\n", samples, monotime.FormatDuration(interval))
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(w, "  min\t%s\n", monotime.FormatFixed(late[0]))
+	fmt.Fprintf(w, "  mean\t%s\n", monotime.FormatFixed(sum/time.Duration(len(late))))
+	fmt.Fprintf(w, "  p50\t%s\n", monotime.FormatFixed(late[len(late)/2]))
+	fmt.Fprintf(w, "  p99\t%s\n", monotime.FormatFixed(late[len(late)*99/100]))
+	fmt.Fprintf(w, "  max\t%s\n", monotime.FormatFixed(late[len(late)-1]))
+	w.Flush()
+}
+
+func read(id int32) (int64, error) {
+	var ts unix.Timespec
+	if err := unix.ClockGettime(id, &ts); err != nil {
+		return 0, err
+	}
+	return ts.Nano(), nil
+}
+
+func sysfs(path string) string {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "unknown"
+	}
+	return strings.TrimSpace(string(b))
+}
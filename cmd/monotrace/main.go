@@ -0,0 +1,99 @@
+// Command monotrace correlates monotime timestamps with kernel trace clocks.
+//
+// Usage:
+//
+//	monotrace mark [-n count] [-interval d]
+//	monotrace fit < trace.txt
+//
+// mark writes correlation markers into the ftrace buffer while a trace is
+// being recorded. fit reads the trace's text output (from the ftrace "trace"
+// file or perf script) and prints the mapping between the trace clock and
+// the monotonic clock.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/thisguycodes/monotime"
+	"github.com/thisguycodes/monotime/tracecorr"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+	switch os.Args[1] {
+	case "mark":
+		mark(os.Args[2:])
+	case "fit":
+		fit()
+	default:
+		usage()
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: monotrace mark [-n count] [-interval d] | monotrace fit < trace.txt")
+	os.Exit(2)
+}
+
+func mark(args []string) {
+	fs := flag.NewFlagSet("mark", flag.ExitOnError)
+	n := fs.Int("n", 10, "number of markers to write")
+	interval := fs.Duration("interval", 100*time.Millisecond, "time between markers")
+	fs.Parse(args)
+
+	f, err := tracecorr.OpenMarker()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	defer f.Close()
+
+	var worst time.Duration
+	for i := 0; i < *n; i++ {
+		if i > 0 {
+			time.Sleep(*interval)
+		}
+		u, err := tracecorr.Mark(f)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		if u > worst {
+			worst = u
+		}
+	}
+	fmt.Printf("wrote %d markers, worst uncertainty %s\n", *n, monotime.FormatDuration(worst))
+}
+
+func fit() {
+	pairs, err := tracecorr.ParseMarkers(os.Stdin)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	c, err := tracecorr.Fit(pairs)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	var worst time.Duration
+	for _, p := range pairs {
+		r := c.ToMono(p.Trace).Sub(p.Mono)
+		if r < 0 {
+			r = -r
+		}
+		if r > worst {
+			worst = r
+		}
+	}
+	fmt.Printf("markers   %d\n", len(pairs))
+	fmt.Printf("offset    %d ns (mono = trace + offset)\n", int64(c.Offset))
+	fmt.Printf("skew      %.3f ppm\n", c.Skew*1e6)
+	fmt.Printf("residual  %s worst\n", monotime.FormatDuration(worst))
+}
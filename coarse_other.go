@@ -0,0 +1,11 @@
+//go:build !linux && !windows
+// +build !linux,!windows
+
+package monotime
+
+// NowCoarse returns the current monotonic time, for hot paths that care little
+// about precision. Where the kernel offers no cheaper, coarser reading of the
+// monotonic clock it is Now.
+func NowCoarse() Time {
+	return Now()
+}
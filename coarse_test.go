@@ -0,0 +1,15 @@
+package monotime
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNowCoarse(t *testing.T) {
+	// The coarse clock is the monotonic clock, at most a tick or so
+	// behind.
+	coarse, now := NowCoarse(), Now()
+	if d := now.Sub(coarse); d < -time.Millisecond || d > 50*time.Millisecond {
+		t.Errorf("NowCoarse %v from Now", d)
+	}
+}
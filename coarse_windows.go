@@ -0,0 +1,21 @@
+package monotime
+
+import "unsafe"
+
+var procQueryUnbiasedInterruptTime = kernel32.NewProc("QueryUnbiasedInterruptTime")
+
+// NowCoarse returns the current monotonic time from QueryUnbiasedInterruptTime:
+// the same clock as Now, updated only once per timer interrupt (usually
+// 15.6ms), but cheaper to read. It suits hot paths that timestamp often and
+// care little about precision, such as idle-connection tracking. A reading
+// may be up to an interrupt behind one taken by Now at the same moment, so the
+// two must not be compared for ordering. Before Windows 10, where Now reads
+// the performance counter, NowCoarse is Now.
+func NowCoarse() Time {
+	if !hasUnbiasedTime {
+		return Now()
+	}
+	var t uint64
+	procQueryUnbiasedInterruptTime.Call(uintptr(unsafe.Pointer(&t)))
+	return Time(t * 100)
+}
@@ -0,0 +1,63 @@
+// Package compat mirrors the timer API of package time on top of monotime's
+// kernel monotonic timers.
+//
+// Every function here has the same signature as its namesake in package time,
+// so a codebase can be migrated by rewriting its imports:
+//
+//	import time "github.com/thisguycodes/monotime/compat"
+//
+// Duration, Time and the duration constants are aliased so such code keeps
+// compiling; anything else (formatting, parsing, time zones) should still be
+// imported from package time.
+package compat
+
+import (
+	"time"
+
+	"github.com/thisguycodes/monotime"
+)
+
+// Duration is an alias for time.Duration.
+type Duration = time.Duration
+
+// Time is an alias for time.Time.
+type Time = time.Time
+
+// Duration constants, re-exported from package time.
+const (
+	Nanosecond  = time.Nanosecond
+	Microsecond = time.Microsecond
+	Millisecond = time.Millisecond
+	Second      = time.Second
+	Minute      = time.Minute
+	Hour        = time.Hour
+)
+
+// Now returns the current local time. It is time.Now: the result already
+// carries a reading of the monotonic clock, which Since, Until and Time.Sub
+// use in preference to the wall clock.
+func Now() Time {
+	return time.Now()
+}
+
+// Since returns the time elapsed since t, measured on the monotonic clock
+// when t carries a monotonic reading.
+func Since(t Time) Duration {
+	return time.Since(t)
+}
+
+// Until returns the duration until t, measured on the monotonic clock when t
+// carries a monotonic reading.
+func Until(t Time) Duration {
+	return time.Until(t)
+}
+
+// Sleep pauses the current goroutine for at least the duration d, measured on
+// the kernel monotonic clock. A negative or zero duration causes Sleep to
+// return immediately.
+func Sleep(d Duration) {
+	if d <= 0 {
+		return
+	}
+	monotime.SleepUntil(monotime.Now().Add(d))
+}
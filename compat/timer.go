@@ -0,0 +1,134 @@
+package compat
+
+import (
+	"sync"
+	"time"
+
+	"github.com/thisguycodes/monotime"
+)
+
+// Ticker is a time.Ticker driven by a kernel monotonic timer.
+type Ticker = monotime.TimeTicker
+
+// NewTicker returns a new Ticker containing a channel that will send the
+// current time on the channel after each tick. The period of the ticks is
+// specified by the duration argument. The duration d must be greater than
+// zero; if not, NewTicker will panic. Stop the ticker to release associated
+// resources.
+func NewTicker(d Duration) *Ticker {
+	return monotime.NewTimeTicker(d)
+}
+
+// Tick is a convenience wrapper for NewTicker providing access to the ticking
+// channel only. Unlike NewTicker, Tick will return nil if d <= 0. The
+// underlying Ticker can never be stopped.
+func Tick(d Duration) <-chan Time {
+	if d <= 0 {
+		return nil
+	}
+	return NewTicker(d).C
+}
+
+// Timer is a time.Timer driven by a kernel monotonic timer. The Timer type
+// represents a single event. When the Timer expires, the current time will be
+// sent on C, unless the Timer was created by AfterFunc.
+type Timer struct {
+	C <-chan Time
+
+	c chan Time
+	f func()
+
+	mu    sync.Mutex
+	timer *monotime.Timer
+	quit  chan struct{}
+}
+
+// NewTimer creates a new Timer that will send the current time on its channel
+// after at least duration d.
+func NewTimer(d Duration) *Timer {
+	c := make(chan Time, 1)
+	t := &Timer{C: c, c: c}
+	t.start(d)
+	return t
+}
+
+// After waits for the duration to elapse and then sends the current time on
+// the returned channel. It is equivalent to NewTimer(d).C.
+func After(d Duration) <-chan Time {
+	return NewTimer(d).C
+}
+
+// AfterFunc waits for the duration to elapse and then calls f in its own
+// goroutine. It returns a Timer that can be used to cancel the call using its
+// Stop method.
+func AfterFunc(d Duration, f func()) *Timer {
+	t := &Timer{f: f}
+	t.start(d)
+	return t
+}
+
+// Stop prevents the Timer from firing. It returns true if the call stops the
+// timer, false if the timer has already expired or been stopped. Stop does
+// not close the channel, to prevent a read from the channel succeeding
+// incorrectly.
+func (t *Timer) Stop() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.stop()
+}
+
+// Reset changes the timer to expire after duration d. It returns true if the
+// timer had been active, false if the timer had expired or been stopped.
+func (t *Timer) Reset(d Duration) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	active := t.stop()
+	t.start(d)
+	return active
+}
+
+// start arms the timer; t.mu must be held or t unpublished.
+func (t *Timer) start(d Duration) {
+	timer := monotime.NewTimerAt(monotime.Now().Add(d))
+	quit := make(chan struct{})
+	t.timer, t.quit = timer, quit
+	go t.wait(timer, quit)
+}
+
+// stop disarms the timer, reporting whether it was armed; t.mu must be held.
+func (t *Timer) stop() bool {
+	if t.timer == nil {
+		return false
+	}
+	close(t.quit)
+	t.timer.Stop()
+	t.timer = nil
+	return true
+}
+
+func (t *Timer) wait(timer *monotime.Timer, quit chan struct{}) {
+	select {
+	case <-timer.C:
+	case <-quit:
+		return
+	}
+
+	t.mu.Lock()
+	if t.timer != timer {
+		// Stopped or reset while we were waking up.
+		t.mu.Unlock()
+		return
+	}
+	t.timer.Stop()
+	t.timer = nil
+	t.mu.Unlock()
+
+	if t.f != nil {
+		go t.f()
+		return
+	}
+	select {
+	case t.c <- time.Now():
+	default:
+	}
+}
@@ -0,0 +1,71 @@
+package compat
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestTimerStopReset(t *testing.T) {
+	timer := NewTimer(time.Hour)
+	if !timer.Stop() {
+		t.Error("Stop of an active timer returned false")
+	}
+	if timer.Stop() {
+		t.Error("second Stop returned true")
+	}
+	if timer.Reset(time.Millisecond) {
+		t.Error("Reset of a stopped timer returned true")
+	}
+	select {
+	case <-timer.C:
+	case <-time.After(time.Second):
+		t.Fatal("reset timer did not fire")
+	}
+	if timer.Stop() {
+		t.Error("Stop of a fired timer returned true")
+	}
+}
+
+func TestAfterFunc(t *testing.T) {
+	var called int32
+	done := make(chan struct{})
+	AfterFunc(time.Millisecond, func() {
+		atomic.AddInt32(&called, 1)
+		close(done)
+	})
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("AfterFunc did not call f")
+	}
+
+	stopped := AfterFunc(time.Millisecond, func() { atomic.AddInt32(&called, 1) })
+	stopped.Stop()
+	time.Sleep(10 * time.Millisecond)
+	if n := atomic.LoadInt32(&called); n != 1 {
+		t.Errorf("f called %d times, want 1", n)
+	}
+}
+
+func TestTicker(t *testing.T) {
+	ticker := NewTicker(time.Millisecond)
+	defer ticker.Stop()
+	prev := <-ticker.C
+	next := <-ticker.C
+	if !next.After(prev) {
+		t.Errorf("ticks at %v then %v", prev, next)
+	}
+	if Tick(0) != nil {
+		t.Error("Tick(0) is not nil")
+	}
+}
+
+func TestSleep(t *testing.T) {
+	start := time.Now()
+	Sleep(2 * time.Millisecond)
+	if d := time.Since(start); d < 2*time.Millisecond {
+		t.Errorf("Sleep(2ms) returned after %v", d)
+	}
+	Sleep(-time.Second)
+}
@@ -0,0 +1,106 @@
+package monotime
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// compensationShift sets how much of each tick's lateness is added to the
+// bias estimate: 1/16, enough to smooth out scheduling noise while settling
+// within a few dozen ticks.
+const compensationShift = 4
+
+// CompensatedTicker is a Ticker that learns how late its ticks are delivered
+// and arms the kernel timer early by that much, so ticks reach the receiver on
+// schedule rather than consistently behind it. Ticks stay on the grid start +
+// n*d, so the long-run mean period is exactly d even on hosts whose timers
+// fire with a large constant overshoot.
+type CompensatedTicker struct {
+	// C receives the due time of each scheduled tick, on the grid rather
+	// than the early time the timer was armed for.
+	C <-chan Time
+
+	kt   *kernelTimer
+	bias int64 // atomic time.Duration
+}
+
+// NewCompensatedTicker returns a CompensatedTicker whose first tick is due d
+// from now, and every d after that. d must be greater than zero; if not,
+// NewCompensatedTicker will panic.
+func NewCompensatedTicker(d time.Duration) *CompensatedTicker {
+	if d <= 0 {
+		panic("non-positive interval for NewCompensatedTicker")
+	}
+
+	kt, err := newKernelTimer()
+	if err != nil {
+		panic(err)
+	}
+	due := Now().Add(d)
+	if err := kt.arm(due, 0); err != nil {
+		kt.release()
+		panic(err)
+	}
+
+	c := make(chan Time)
+	t := &CompensatedTicker{C: c, kt: kt}
+	kt.start(func(uint64) bool {
+		select {
+		case c <- due:
+		case <-kt.done:
+			return false
+		}
+
+		bias := time.Duration(atomic.LoadInt64(&t.bias))
+		bias = nextBias(bias, Since(due), d)
+		atomic.StoreInt64(&t.bias, int64(bias))
+
+		due = due.Add(d)
+		if err := kt.arm(due.Add(-bias), 0); err != nil {
+			kt.fail(err)
+			return false
+		}
+		return true
+	})
+	return t
+}
+
+// nextBias updates the bias of a ticker with interval d after a tick arrived
+// late past its due time. Lateness is measured on the grid, not from the
+// early arm time, so it is what is left over after the current bias: the
+// estimate integrates it, and settles once ticks arrive on time. Each sample
+// is capped at d/2, so that one descheduled tick can't throw the estimate far
+// off.
+func nextBias(bias, late, d time.Duration) time.Duration {
+	if late > d/2 {
+		late = d / 2
+	}
+	if late < -d/2 {
+		late = -d / 2
+	}
+	bias += late >> compensationShift
+	if bias < 0 {
+		bias = 0
+	}
+	if bias > d/2 {
+		bias = d / 2
+	}
+	return bias
+}
+
+// Bias returns how early the ticker currently arms its timer to make up for
+// delivery latency.
+func (t *CompensatedTicker) Bias() time.Duration {
+	return time.Duration(atomic.LoadInt64(&t.bias))
+}
+
+// Stop turns off the ticker, as Ticker.Stop does.
+func (t *CompensatedTicker) Stop() {
+	t.kt.stop()
+}
+
+// Err returns a channel that receives the error that ended the ticker, should
+// its kernel timer fail, as Ticker.Err does.
+func (t *CompensatedTicker) Err() <-chan error {
+	return t.kt.errc
+}
@@ -0,0 +1,76 @@
+package monotime
+
+import (
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestNextBiasConverges(t *testing.T) {
+	const d = 2 * time.Millisecond
+	for _, overshoot := range []time.Duration{0, 30 * time.Microsecond, 400 * time.Microsecond, 5 * time.Millisecond} {
+		// A timer armed bias early fires overshoot after it is armed.
+		var bias, late time.Duration
+		for i := 0; i < 500; i++ {
+			late = overshoot - bias
+			bias = nextBias(bias, late, d)
+		}
+		want := overshoot
+		if want > d/2 {
+			want = d / 2
+		}
+		if diff := bias - want; diff < -time.Microsecond || diff > time.Microsecond {
+			t.Errorf("overshoot %v: bias settled at %v, want %v", overshoot, bias, want)
+		}
+		if overshoot <= d/2 && (late < -time.Microsecond || late > time.Microsecond) {
+			t.Errorf("overshoot %v: ticks still %v late", overshoot, late)
+		}
+	}
+}
+
+func TestNextBiasClamps(t *testing.T) {
+	const d = time.Millisecond
+	if got := nextBias(0, -time.Second, d); got != 0 {
+		t.Errorf("bias after early tick = %v, want 0", got)
+	}
+	if got, want := nextBias(0, time.Hour, d), d/2>>compensationShift; got != want {
+		t.Errorf("bias after very late tick = %v, want %v", got, want)
+	}
+	if got := nextBias(d/2, d/2, d); got != d/2 {
+		t.Errorf("bias = %v, want it capped at %v", got, d/2)
+	}
+}
+
+func TestCompensatedTickerConverges(t *testing.T) {
+	// Scheduling noise on a loaded host can hold the median off for a
+	// while; only a ticker that stays late on every attempt fails.
+	var median, bias time.Duration
+	for attempt := 0; attempt < 3; attempt++ {
+		median, bias = compensatedLateness()
+		if median <= 100*time.Microsecond && median >= -100*time.Microsecond {
+			return
+		}
+	}
+	t.Errorf("median lateness %v with bias %v, want about 0", median, bias)
+}
+
+// compensatedLateness runs a CompensatedTicker until its bias settles, then
+// returns the median lateness of its ticks against the grid and the bias.
+func compensatedLateness() (median, bias time.Duration) {
+	const d = 2 * time.Millisecond
+	start := Now()
+	ticker := NewCompensatedTicker(d)
+	defer ticker.Stop()
+
+	// The median keeps the odd descheduled tick from swamping the result.
+	const settle, measure = 200, 201
+	late := make([]time.Duration, 0, measure)
+	for i := 1; i <= settle+measure; i++ {
+		<-ticker.C
+		if i > settle {
+			late = append(late, Now().Sub(start.Add(time.Duration(i)*d)))
+		}
+	}
+	sort.Slice(late, func(i, j int) bool { return late[i] < late[j] })
+	return late[measure/2], ticker.Bias()
+}
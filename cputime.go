@@ -0,0 +1,12 @@
+//go:build darwin || linux || windows
+// +build darwin linux windows
+
+package monotime
+
+// StartCPUStopwatch returns a running Stopwatch on the clock NowProcessCPU
+// reads, which measures the CPU time the process consumes rather than the
+// time that passes: a goroutine blocked on I/O adds nothing to it, and work
+// spread across several threads adds the time on each.
+func StartCPUStopwatch() *Stopwatch {
+	return StartStopwatchOn(NowProcessCPU)
+}
@@ -0,0 +1,20 @@
+package monotime
+
+import (
+	"fmt"
+
+	"golang.org/x/sys/unix"
+)
+
+// NowProcessCPU returns the CPU time consumed so far by every thread in the
+// process, from CLOCK_PROCESS_CPUTIME_ID. Its readings are durations since
+// the process started, and must not be mixed with Now's.
+func NowProcessCPU() Time {
+	spec := new(unix.Timespec)
+	err := unix.ClockGettime(unix.CLOCK_PROCESS_CPUTIME_ID, spec)
+	if err != nil {
+		err = fmt.Errorf("Error getting process CPU time from the kernel: %w", err)
+		panic(err)
+	}
+	return Time(spec.Nano())
+}
@@ -0,0 +1,10 @@
+package monotime
+
+import "golang.org/x/sys/unix"
+
+// NowProcessCPU returns the CPU time consumed so far by every thread in the
+// process, from CLOCK_PROCESS_CPUTIME_ID. Its readings are durations since
+// the process started, and must not be mixed with Now's.
+func NowProcessCPU() Time {
+	return readClock(unix.CLOCK_PROCESS_CPUTIME_ID)
+}
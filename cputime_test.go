@@ -0,0 +1,33 @@
+//go:build darwin || linux || windows
+// +build darwin linux windows
+
+package monotime
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCPUStopwatch(t *testing.T) {
+	s := StartCPUStopwatch()
+	time.Sleep(50 * time.Millisecond)
+	idle := s.Lap()
+
+	// Spin until the process has visibly used CPU time, which Windows
+	// charges only once per clock interrupt.
+	start := NowProcessCPU()
+	deadline := Now().Add(5 * time.Second)
+	for NowProcessCPU().Sub(start) < 50*time.Millisecond {
+		if Now().Sub(deadline) > 0 {
+			t.Fatal("process CPU time did not advance while spinning")
+		}
+	}
+	busy := s.Lap()
+
+	if idle >= 40*time.Millisecond {
+		t.Errorf("sleeping 50ms used %v of CPU time", idle)
+	}
+	if busy < 50*time.Millisecond {
+		t.Errorf("spinning for 50ms of CPU time measured %v", busy)
+	}
+}
@@ -0,0 +1,26 @@
+package monotime
+
+import (
+	"fmt"
+	"syscall"
+)
+
+// NowProcessCPU returns the CPU time consumed so far by every thread in the
+// process, in user and kernel mode, from GetProcessTimes. Its readings are
+// durations since the process started, and must not be mixed with Now's.
+// Windows charges CPU time once per clock interrupt, so short measurements
+// are coarse; see Resolution.
+func NowProcessCPU() Time {
+	var creation, exit, kernel, user syscall.Filetime
+	h, _ := syscall.GetCurrentProcess()
+	if err := syscall.GetProcessTimes(h, &creation, &exit, &kernel, &user); err != nil {
+		err = fmt.Errorf("Error getting process CPU time from the kernel: %w", err)
+		panic(err)
+	}
+	return Time(filetimeNanos(kernel) + filetimeNanos(user))
+}
+
+// filetimeNanos returns a FILETIME duration, in 100ns units, in nanoseconds.
+func filetimeNanos(ft syscall.Filetime) int64 {
+	return (int64(ft.HighDateTime)<<32 | int64(ft.LowDateTime)) * 100
+}
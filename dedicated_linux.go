@@ -0,0 +1,17 @@
+package monotime
+
+// WithDedicatedThread services the ticker's kernel timer from a goroutine
+// locked to an OS thread of its own, pinned to the given CPUs if any are
+// listed. Keeping the Go scheduler from moving the timer's goroutine between
+// threads takes some jitter out of tick delivery, which matters for the
+// handful of timers that drive real-time control loops; the thread is
+// discarded when the ticker is stopped. NewTicker fails if the CPU affinity
+// cannot be set.
+func WithDedicatedThread(cpus ...int) TickerOption {
+	cpus = append([]int(nil), cpus...)
+	return func(cfg *tickerConfig) {
+		cfg.launch = func(kt *kernelTimer, fire func(n uint64) bool) error {
+			return kt.startLocked(cpus, fire)
+		}
+	}
+}
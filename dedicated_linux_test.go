@@ -0,0 +1,21 @@
+package monotime
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDedicatedThread(t *testing.T) {
+	ticker, err := NewTickerErr(time.Millisecond, WithDedicatedThread(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ticker.Stop()
+	<-ticker.C
+
+	before := openFDs(t)
+	if _, err := NewTickerErr(time.Millisecond, WithDedicatedThread(-1)); err == nil {
+		t.Fatal("ticker pinned to CPU -1")
+	}
+	waitFDs(t, before)
+}
@@ -0,0 +1,155 @@
+package monotime
+
+import (
+	"sync"
+	"time"
+)
+
+// DeferrableTimer is a one-shot timer that may fire up to a bound late, so
+// that it can share a wakeup with every other deferrable timer of the same
+// bound. Deferrable timers with a given bound are batched onto the ticks of a
+// single shared wheel, which only runs while such timers are pending; a
+// process full of idle timeouts then costs one wakeup per bound instead of one
+// per timer. They suit battery-sensitive background work.
+type DeferrableTimer struct {
+	// C receives the time of the wheel tick the timer fired on.
+	C <-chan Time
+
+	c    chan Time
+	w    *wheel
+	slot int64
+}
+
+// NewDeferrableTimer returns a DeferrableTimer that fires on the first tick of
+// the shared wheel for bound at or after t: no earlier than t and, barring
+// scheduling delays, no later than t+bound. bound must be greater than zero;
+// if not, NewDeferrableTimer will panic.
+func NewDeferrableTimer(t Time, bound time.Duration) *DeferrableTimer {
+	if bound <= 0 {
+		panic("non-positive bound for NewDeferrableTimer")
+	}
+	c := make(chan Time, 1)
+	dt := &DeferrableTimer{C: c, c: c}
+	wheelFor(bound).add(dt, t)
+	return dt
+}
+
+// Stop prevents the timer from firing. It returns true if the call stops the
+// timer, false if it has already fired or been stopped.
+func (t *DeferrableTimer) Stop() bool {
+	return t.w.remove(t)
+}
+
+var (
+	wheelsMu sync.Mutex
+	wheels   = map[time.Duration]*wheel{}
+)
+
+func wheelFor(bound time.Duration) *wheel {
+	wheelsMu.Lock()
+	defer wheelsMu.Unlock()
+	w, ok := wheels[bound]
+	if !ok {
+		w = &wheel{tick: bound, slots: map[int64]map[*DeferrableTimer]struct{}{}}
+		wheels[bound] = w
+	}
+	return w
+}
+
+// wheel batches deferrable timers into slots one tick wide and fires a slot's
+// timers together on the tick that ends it.
+type wheel struct {
+	tick time.Duration
+
+	mu      sync.Mutex
+	slots   map[int64]map[*DeferrableTimer]struct{}
+	pending int
+	ticker  *Ticker
+	quit    chan struct{}
+}
+
+func (w *wheel) add(t *DeferrableTimer, at Time) {
+	slot := int64(at) / int64(w.tick)
+	if int64(at)%int64(w.tick) != 0 {
+		slot++
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	t.w, t.slot = w, slot
+	s, ok := w.slots[slot]
+	if !ok {
+		s = map[*DeferrableTimer]struct{}{}
+		w.slots[slot] = s
+	}
+	s[t] = struct{}{}
+	w.pending++
+
+	if w.ticker == nil {
+		// Align ticks to the grid so wheels in every process share
+		// their wakeups too.
+		start := Now().Truncate(w.tick).Add(w.tick)
+		w.ticker = NewTicker(w.tick, WithStartAt(start))
+		w.quit = make(chan struct{})
+		go w.run(w.ticker, w.quit)
+	}
+}
+
+func (w *wheel) remove(t *DeferrableTimer) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	s, ok := w.slots[t.slot]
+	if !ok {
+		return false
+	}
+	if _, ok := s[t]; !ok {
+		return false
+	}
+	delete(s, t)
+	if len(s) == 0 {
+		delete(w.slots, t.slot)
+	}
+	w.pending--
+	w.idle()
+	return true
+}
+
+// idle stops the wheel's ticker once no timers are pending; w.mu must be
+// held.
+func (w *wheel) idle() {
+	if w.pending > 0 || w.ticker == nil {
+		return
+	}
+	close(w.quit)
+	w.ticker.Stop()
+	w.ticker, w.quit = nil, nil
+}
+
+func (w *wheel) run(ticker *Ticker, quit <-chan struct{}) {
+	for {
+		var due Time
+		select {
+		case due = <-ticker.C:
+		case <-quit:
+			return
+		}
+
+		now := int64(Now()) / int64(w.tick)
+		w.mu.Lock()
+		for slot, s := range w.slots {
+			if slot > now {
+				continue
+			}
+			for t := range s {
+				select {
+				case t.c <- due:
+				default:
+				}
+			}
+			w.pending -= len(s)
+			delete(w.slots, slot)
+		}
+		w.idle()
+		w.mu.Unlock()
+	}
+}
@@ -0,0 +1,63 @@
+package monotime
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDeferrableTimer(t *testing.T) {
+	const bound = 5 * time.Millisecond
+	// Timers due within one tick of the wheel fire together, on the tick
+	// that ends their slot.
+	at := Now().Truncate(bound).Add(bound + time.Millisecond)
+	timers := []*DeferrableTimer{NewDeferrableTimer(at, bound), NewDeferrableTimer(at.Add(time.Millisecond), bound)}
+	var ticks []Time
+	for i, timer := range timers {
+		select {
+		case tick := <-timer.C:
+			if tick.Before(at) || tick.Sub(at) > bound {
+				t.Errorf("timer %d fired on the tick at %v, want within %v after %v", i, tick, bound, at)
+			}
+			ticks = append(ticks, tick)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timer %d did not fire", i)
+		}
+	}
+	if ticks[0] != ticks[1] {
+		t.Errorf("timers in one slot fired on ticks %v and %v", ticks[0], ticks[1])
+	}
+}
+
+func TestDeferrableTimerStop(t *testing.T) {
+	const bound = time.Millisecond
+	timer := NewDeferrableTimer(Now().Add(bound), bound)
+	if !timer.Stop() {
+		t.Error("Stop of a pending timer returned false")
+	}
+	if timer.Stop() {
+		t.Error("second Stop returned true")
+	}
+	select {
+	case <-timer.C:
+		t.Error("stopped timer fired")
+	case <-time.After(10 * bound):
+	}
+
+	// With no timers left the wheel stops ticking.
+	w := wheelFor(bound)
+	w.mu.Lock()
+	running := w.ticker != nil
+	w.mu.Unlock()
+	if running {
+		t.Error("wheel still ticking with no timers pending")
+	}
+}
+
+func TestNewDeferrableTimerPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("NewDeferrableTimer with a zero bound did not panic")
+		}
+	}()
+	NewDeferrableTimer(Now(), 0)
+}
@@ -0,0 +1,10 @@
+//go:build armbe || arm64be || mips || mips64 || mips64p32 || ppc || ppc64 || s390 || s390x || sparc || sparc64
+// +build armbe arm64be mips mips64 mips64p32 ppc ppc64 s390 s390x sparc sparc64
+
+package monotime
+
+import "encoding/binary"
+
+// hostOrder is the byte order of the host, in which the kernel writes
+// integers to descriptors such as a timerfd.
+var hostOrder binary.ByteOrder = binary.BigEndian
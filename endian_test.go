@@ -0,0 +1,16 @@
+package monotime
+
+import (
+	"testing"
+	"unsafe"
+)
+
+func TestHostOrder(t *testing.T) {
+	// The kernel writes integers in memory order, which hostOrder must
+	// decode whatever the architecture.
+	want := uint64(0x0102030405060708)
+	buf := *(*[8]byte)(unsafe.Pointer(&want))
+	if got := hostOrder.Uint64(buf[:]); got != want {
+		t.Errorf("hostOrder decodes %x from %x, want %x", got, buf, want)
+	}
+}
@@ -0,0 +1,66 @@
+package monotime
+
+import (
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// openFDs returns the number of descriptors the process has open.
+func openFDs(t *testing.T) int {
+	t.Helper()
+	fds, err := ioutil.ReadDir("/proc/self/fd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return len(fds)
+}
+
+// waitFDs waits for the number of open descriptors to fall to at most want,
+// as exiting timer goroutines release theirs.
+func waitFDs(t *testing.T, want int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		n := openFDs(t)
+		if n <= want {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("%d descriptors open, want at most %d", n, want)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// exhaustFDs lowers the process's descriptor limit and uses up every
+// descriptor below it, so that opening another fails with EMFILE, until the
+// test ends.
+func exhaustFDs(t *testing.T) {
+	t.Helper()
+	var lim unix.Rlimit
+	if err := unix.Getrlimit(unix.RLIMIT_NOFILE, &lim); err != nil {
+		t.Fatal(err)
+	}
+	low := lim
+	low.Cur = uint64(openFDs(t))
+	if err := unix.Setrlimit(unix.RLIMIT_NOFILE, &low); err != nil {
+		t.Fatal(err)
+	}
+	var held []int
+	t.Cleanup(func() {
+		for _, fd := range held {
+			unix.Close(fd)
+		}
+		unix.Setrlimit(unix.RLIMIT_NOFILE, &lim)
+	})
+	for {
+		fd, err := unix.Open("/dev/null", unix.O_RDONLY|unix.O_CLOEXEC, 0)
+		if err != nil {
+			return
+		}
+		held = append(held, fd)
+	}
+}
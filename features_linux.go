@@ -0,0 +1,113 @@
+package monotime
+
+import (
+	"io/ioutil"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// ClockInfo describes a kernel clock available on this host.
+type ClockInfo struct {
+	// Name is the clock's kernel name, e.g. "CLOCK_MONOTONIC".
+	Name string
+	// Resolution is the resolution advertised by clock_getres.
+	Resolution time.Duration
+}
+
+// Features describes the timekeeping facilities this host offers, whether or
+// not the package is currently using them.
+type Features struct {
+	// Clocks lists the clocks clock_gettime accepts.
+	Clocks []ClockInfo
+
+	// Timerfd, IOUring and Kqueue report which kernel timer mechanisms
+	// are available. Tickers use io_uring only if created WithIOUring.
+	Timerfd bool
+	IOUring bool
+	Kqueue  bool
+
+	// VDSO reports whether the kernel maps a vDSO, which lets clocks be
+	// read without entering the kernel.
+	VDSO bool
+
+	// TSC reports whether the kernel's clocksource is the CPU's
+	// timestamp counter, the cheapest and usually most precise source.
+	TSC bool
+
+	// Clocksource is the kernel's current clocksource, e.g. "tsc" or
+	// "kvm-clock", or "" if it could not be determined.
+	Clocksource string
+
+	// WakeAlarm reports whether the process may create timers that wake
+	// the system from suspend; see CheckWakeAlarm.
+	WakeAlarm bool
+}
+
+var (
+	capsOnce sync.Once
+	caps     Features
+)
+
+// Capabilities probes the host for the timekeeping facilities it offers. The
+// probe runs once; later calls return the same result.
+func Capabilities() Features {
+	capsOnce.Do(func() {
+		caps = probeCapabilities()
+	})
+	c := caps
+	c.Clocks = append([]ClockInfo(nil), caps.Clocks...)
+	return c
+}
+
+var probedClocks = []struct {
+	name string
+	id   int32
+}{
+	{"CLOCK_MONOTONIC", unix.CLOCK_MONOTONIC},
+	{"CLOCK_MONOTONIC_RAW", unix.CLOCK_MONOTONIC_RAW},
+	{"CLOCK_MONOTONIC_COARSE", unix.CLOCK_MONOTONIC_COARSE},
+	{"CLOCK_BOOTTIME", unix.CLOCK_BOOTTIME},
+	{"CLOCK_REALTIME", unix.CLOCK_REALTIME},
+	{"CLOCK_REALTIME_COARSE", unix.CLOCK_REALTIME_COARSE},
+	{"CLOCK_TAI", unix.CLOCK_TAI},
+	{"CLOCK_PROCESS_CPUTIME_ID", unix.CLOCK_PROCESS_CPUTIME_ID},
+	{"CLOCK_THREAD_CPUTIME_ID", unix.CLOCK_THREAD_CPUTIME_ID},
+	{"CLOCK_REALTIME_ALARM", unix.CLOCK_REALTIME_ALARM},
+	{"CLOCK_BOOTTIME_ALARM", unix.CLOCK_BOOTTIME_ALARM},
+}
+
+func probeCapabilities() Features {
+	var c Features
+	for _, pc := range probedClocks {
+		var ts unix.Timespec
+		if err := unix.ClockGetres(pc.id, &ts); err != nil {
+			continue
+		}
+		c.Clocks = append(c.Clocks, ClockInfo{Name: pc.name, Resolution: time.Duration(ts.Nano())})
+	}
+
+	if fd, err := unix.TimerfdCreate(unix.CLOCK_MONOTONIC, unix.TFD_CLOEXEC); err == nil {
+		unix.Close(fd)
+		c.Timerfd = true
+	}
+
+	// io_uring_setup with a nil params pointer fails with EFAULT when the
+	// syscall exists, and ENOSYS (or EPERM under seccomp) when it doesn't.
+	_, _, errno := unix.Syscall(unix.SYS_IO_URING_SETUP, 1, 0, 0)
+	c.IOUring = errno == unix.EFAULT || errno == unix.EINVAL
+
+	if maps, err := ioutil.ReadFile("/proc/self/maps"); err == nil {
+		c.VDSO = strings.Contains(string(maps), "[vdso]")
+	}
+
+	if cs, err := ioutil.ReadFile("/sys/devices/system/clocksource/clocksource0/current_clocksource"); err == nil {
+		c.Clocksource = strings.TrimSpace(string(cs))
+		c.TSC = c.Clocksource == "tsc"
+	}
+
+	c.WakeAlarm = CheckWakeAlarm() == nil
+	return c
+}
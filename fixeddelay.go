@@ -0,0 +1,56 @@
+package monotime
+
+import "time"
+
+// WithFixedDelay makes each of the ticker's ticks after the first come due d
+// after the receiver calls Ack to say it has finished with the previous one.
+// The gap between the end of one job and the start of the next is then always
+// d, however long the jobs take, and jobs can never overlap or pile up: a
+// fixed-delay ticker never has a tick waiting that the receiver has not
+// acknowledged.
+func WithFixedDelay() TickerOption {
+	return func(cfg *tickerConfig) {
+		cfg.mode = fixedDelay
+	}
+}
+
+// fireFixedDelay returns the kernel timer callback for a fixed-delay ticker
+// whose first tick is due at due, with delay d: it delivers the tick, waits
+// for Ack, and arms the one-shot timer d later.
+func (t *tickerState) fireFixedDelay(due Time, d time.Duration) func(n uint64) bool {
+	pause := t.pausec
+	return func(uint64) bool {
+		if t.paused(&pause, &due) {
+			return true
+		}
+		if sent, ok := t.send(due, pause); !ok {
+			return false
+		} else if !sent {
+			return true
+		}
+		t.checkLag(due, 0)
+		select {
+		case <-t.ack:
+		case <-pause:
+			return true
+		case <-t.kt.done:
+			return false
+		}
+		due = t.now().Add(d)
+		return t.rearm(pause, due)
+	}
+}
+
+// Ack tells a ticker created WithFixedDelay that the receiver has finished
+// with the latest tick, starting the delay to the next. It should be called
+// once per tick received; an Ack that comes before its tick does starts the
+// delay as soon as the tick is sent. Ack has no effect on other tickers.
+func (t *Ticker) Ack() {
+	if t.ack == nil {
+		return
+	}
+	select {
+	case t.ack <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,44 @@
+package monotime
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFixedDelayTicker(t *testing.T) {
+	const d = 2 * time.Millisecond
+	ticker := NewTicker(d, WithFixedDelay())
+	defer ticker.Stop()
+
+	for i := 0; i < 3; i++ {
+		<-ticker.C
+		time.Sleep(5 * d) // a job longer than the delay
+		done := Now()
+		ticker.Ack()
+		due := <-ticker.C
+		if gap := due.Sub(done); gap < d {
+			t.Errorf("next tick due %v after the Ack, want at least %v", gap, d)
+		}
+		if now := Now(); now < due {
+			t.Errorf("tick received %v before it was due", due.Sub(now))
+		}
+		ticker.Ack()
+	}
+}
+
+func TestFixedDelayTickerWaitsForAck(t *testing.T) {
+	ticker := NewTicker(time.Millisecond, WithFixedDelay())
+	defer ticker.Stop()
+	<-ticker.C
+	select {
+	case <-ticker.C:
+		t.Fatal("tick before the previous one was acknowledged")
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+func TestAckOnTicker(t *testing.T) {
+	ticker := NewTicker(time.Hour)
+	defer ticker.Stop()
+	ticker.Ack() // no effect
+}
@@ -0,0 +1,39 @@
+package monotime
+
+import "time"
+
+// WithFixedRate makes the ticker's ticks come due at exactly t0+n*d. A Ticker
+// otherwise leaves the period to the kernel, which on some platforms keeps it
+// in coarser units or adds each expiration's overshoot to the next; a
+// fixed-rate ticker instead arms its kernel timer once per tick, at the
+// absolute time the next tick is due, so neither rounding nor processing time
+// can accumulate into drift. That costs a system call per tick. Ticks missed
+// while the receiver is busy are delivered as the ticker's missed-tick policy
+// says.
+func WithFixedRate() TickerOption {
+	return func(cfg *tickerConfig) {
+		cfg.mode = fixedRate
+	}
+}
+
+// fireFixedRate returns the kernel timer callback for a fixed-rate ticker
+// whose first tick is due at due and every d after. Each expiration of the
+// one-shot timer stands for every tick that has come due since the last.
+func (t *tickerState) fireFixedRate(due Time, d time.Duration) func(n uint64) bool {
+	deliver := t.fire(due, d)
+	pause := t.pausec
+	return func(uint64) bool {
+		if t.paused(&pause, &due) {
+			return true
+		}
+		n := uint64(1)
+		if late := t.now().Sub(due); late >= d {
+			n += uint64(late / d)
+		}
+		if !deliver(n) {
+			return false
+		}
+		due = due.Add(time.Duration(n) * d)
+		return t.rearm(pause, due)
+	}
+}
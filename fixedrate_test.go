@@ -0,0 +1,44 @@
+package monotime
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFixedRateTicker(t *testing.T) {
+	const d = 2 * time.Millisecond
+	start := Now()
+	ticker := NewTicker(d, WithFixedRate())
+	defer ticker.Stop()
+
+	first := <-ticker.C
+	if first < start.Add(d) {
+		t.Errorf("first tick due %v after start, want at least %v", first.Sub(start), d)
+	}
+	for i := 1; i <= 5; i++ {
+		// Slow processing does not push later ticks off the grid.
+		time.Sleep(d / 2)
+		due := <-ticker.C
+		if want := first.Add(time.Duration(i) * d); due != want {
+			t.Errorf("tick %d due at %d, want %d", i, due, want)
+		}
+		if now := Now(); now < due {
+			t.Errorf("tick %d received %v before it was due", i, due.Sub(now))
+		}
+	}
+}
+
+func TestFixedRateTickerCatchUp(t *testing.T) {
+	const d = time.Millisecond
+	ticker := NewTicker(d, WithFixedRate())
+	defer ticker.Stop()
+
+	first := <-ticker.C
+	time.Sleep(10 * d)
+	// Every tick missed while asleep is still delivered, on the grid.
+	for i := 1; i <= 10; i++ {
+		if due, want := <-ticker.C, first.Add(time.Duration(i)*d); due != want {
+			t.Fatalf("tick %d due at %d, want %d", i, due, want)
+		}
+	}
+}
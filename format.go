@@ -0,0 +1,144 @@
+package monotime
+
+import (
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// fixedWidth is the width, in characters, of FormatFixed's output for
+// durations under 1000 seconds.
+const fixedWidth = 7
+
+const (
+	minDuration time.Duration = -1 << 63
+	maxDuration time.Duration = 1<<63 - 1
+)
+
+// FormatDuration formats d compactly for people to read. Durations of a minute
+// or more are rounded to the second and written with fixed two-digit minutes
+// and seconds, as in "1h02m03s" or "4m05s". Shorter ones are written to three
+// significant digits in the largest SI unit that keeps them at least one, as
+// in "1.5s", "12.3ms", "250µs" or "830ns". ParseDuration reads the result
+// back, to within the rounding, for every Duration.
+func FormatDuration(d time.Duration) string {
+	if d < 0 {
+		if d == minDuration {
+			d++ // -d would overflow
+		}
+		return "-" + FormatDuration(-d)
+	}
+	if round3(d) < time.Minute {
+		return formatSI(d, true)
+	}
+
+	s := int64(d / time.Second)
+	// Round to the nearest second, but never past the largest Duration,
+	// which ParseDuration could not read back.
+	if d%time.Second >= time.Second/2 && s < int64(maxDuration/time.Second) {
+		s++
+	}
+	h, m, s := s/3600, s/60%60, s%60
+	var b []byte
+	if h > 0 {
+		b = strconv.AppendInt(b, h, 10)
+		b = append(b, 'h')
+	}
+	if h > 0 && m < 10 {
+		b = append(b, '0')
+	}
+	b = strconv.AppendInt(b, m, 10)
+	b = append(b, 'm')
+	if s < 10 {
+		b = append(b, '0')
+	}
+	b = strconv.AppendInt(b, s, 10)
+	b = append(b, 's')
+	return string(b)
+}
+
+// FormatFixed formats d to three significant digits in SI units, keeping
+// trailing zeros and padding on the left so that durations under 1000
+// seconds all take seven characters, as in " 12.0ms" or "  1.50s". It suits
+// columns of measurements. ParseDuration reads the result back.
+func FormatFixed(d time.Duration) string {
+	s := ""
+	if d < 0 {
+		if d == minDuration {
+			d++ // -d would overflow
+		}
+		s = "-" + formatSI(-d, false)
+	} else {
+		s = formatSI(d, false)
+	}
+	if n := utf8.RuneCountInString(s); n < fixedWidth {
+		s = strings.Repeat(" ", fixedWidth-n) + s
+	}
+	return s
+}
+
+// ParseDuration parses a duration written by FormatDuration or FormatFixed,
+// or in any other form time.ParseDuration accepts. Surrounding spaces are
+// ignored.
+func ParseDuration(s string) (time.Duration, error) {
+	return time.ParseDuration(strings.TrimSpace(s))
+}
+
+var siUnits = []struct {
+	unit time.Duration
+	name string
+}{
+	{time.Second, "s"},
+	{time.Millisecond, "ms"},
+	{time.Microsecond, "µs"},
+	{time.Nanosecond, "ns"},
+}
+
+// formatSI formats a non-negative d to three significant digits in the
+// largest SI unit that keeps it at least one, trimming trailing zeros if
+// trim is set.
+func formatSI(d time.Duration, trim bool) string {
+	// Round first, so that rounding up can carry into the next unit:
+	// 999.7µs is "1.00ms", not "1000µs".
+	d = round3(d)
+
+	u := siUnits[len(siUnits)-1]
+	for _, su := range siUnits {
+		if d >= su.unit {
+			u = su
+			break
+		}
+	}
+	whole, frac := int64(d/u.unit), int64(d%u.unit)
+
+	// Three significant digits leave 3 - len(whole) decimals.
+	b := strconv.AppendInt(nil, whole, 10)
+	decimals := 3 - len(b)
+	if u.unit == time.Nanosecond || decimals <= 0 {
+		return string(append(b, u.name...))
+	}
+	digits := strconv.FormatInt(frac+int64(u.unit), 10)[1 : 1+decimals]
+	if trim {
+		digits = strings.TrimRight(digits, "0")
+	}
+	if digits != "" {
+		b = append(b, '.')
+		b = append(b, digits...)
+	}
+	return string(append(b, u.name...))
+}
+
+// round3 rounds a non-negative d to three significant digits.
+func round3(d time.Duration) time.Duration {
+	p := time.Duration(1)
+	for d/p >= 1000 {
+		p *= 10
+	}
+	rem := d % p
+	d -= rem
+	if rem >= (p+1)/2 && p > 1 {
+		d += p
+	}
+	return d
+}
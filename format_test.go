@@ -0,0 +1,80 @@
+package monotime
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestFormatDuration(t *testing.T) {
+	for _, tt := range []struct {
+		d    time.Duration
+		want string
+	}{
+		{0, "0ns"},
+		{830, "830ns"},
+		{250 * time.Microsecond, "250µs"},
+		{999700 * time.Nanosecond, "1ms"},
+		{12340 * time.Microsecond, "12.3ms"},
+		{1500 * time.Millisecond, "1.5s"},
+		{59999 * time.Millisecond, "1m00s"},
+		{4*time.Minute + 5*time.Second, "4m05s"},
+		{time.Hour + 2*time.Minute + 3*time.Second, "1h02m03s"},
+		{-1500 * time.Millisecond, "-1.5s"},
+		{math.MaxInt64, "2562047h47m16s"},
+		{math.MinInt64, "-2562047h47m16s"},
+	} {
+		if got := FormatDuration(tt.d); got != tt.want {
+			t.Errorf("FormatDuration(%d) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
+
+func TestFormatFixed(t *testing.T) {
+	for _, tt := range []struct {
+		d    time.Duration
+		want string
+	}{
+		{12 * time.Millisecond, " 12.0ms"},
+		{1500 * time.Millisecond, "  1.50s"},
+		{830, "  830ns"},
+		{123456 * time.Microsecond, "  123ms"},
+		{-5 * time.Microsecond, "-5.00µs"},
+	} {
+		if got := FormatFixed(tt.d); got != tt.want {
+			t.Errorf("FormatFixed(%d) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
+
+func TestFormatRoundTrip(t *testing.T) {
+	for _, d := range []time.Duration{
+		0, 1, 999, 1000, 999499, 999500, 12345678, time.Second,
+		59*time.Second + 999*time.Millisecond, time.Minute, 90 * time.Minute,
+		1000 * time.Hour, math.MaxInt64, math.MinInt64, math.MinInt64 + 1, -time.Hour,
+	} {
+		for name, format := range map[string]func(time.Duration) string{
+			"FormatDuration": FormatDuration,
+			"FormatFixed":    FormatFixed,
+		} {
+			s := format(d)
+			got, err := ParseDuration(s)
+			if err != nil {
+				t.Errorf("%s(%d) = %q, which ParseDuration rejects: %v", name, d, s, err)
+				continue
+			}
+			// Both formats round: to three significant digits, or to the
+			// second from a minute up.
+			tolerance := d / 200
+			if tolerance < 0 {
+				tolerance = -tolerance
+			}
+			if tolerance < time.Second && (d >= time.Minute || d <= -time.Minute) && name == "FormatDuration" {
+				tolerance = time.Second
+			}
+			if diff := got - d; diff > tolerance || diff < -tolerance {
+				t.Errorf("%s(%d) = %q reads back as %d", name, d, s, got)
+			}
+		}
+	}
+}
@@ -0,0 +1,120 @@
+//go:build !darwin && !dragonfly && !freebsd && !js && !linux && !netbsd && !openbsd && !windows
+// +build !darwin,!dragonfly,!freebsd,!js,!linux,!netbsd,!openbsd,!windows
+
+package monotime
+
+import (
+	"sync"
+	"time"
+)
+
+// kernelTimer is a timer together with the goroutine waiting on it. It is the
+// engine shared by Ticker and Timer; on platforms without a native backend it
+// is a runtime timer, re-armed for each expiration so the schedule stays on
+// the grid however late each one fires.
+type kernelTimer struct {
+	sched relSchedule
+	timer *time.Timer
+
+	mu sync.Mutex // guards timer while arming
+
+	stopOnce sync.Once
+	done     chan struct{}
+	exited   chan struct{}
+	errc     chan error // receives the error that ended the goroutine, if any
+}
+
+// newKernelTimer returns a kernelTimer on the monotonic clock.
+func newKernelTimer() (*kernelTimer, error) {
+	t := time.NewTimer(time.Hour)
+	t.Stop()
+	return &kernelTimer{
+		sched:  relSchedule{now: Now},
+		timer:  t,
+		done:   make(chan struct{}),
+		exited: make(chan struct{}),
+		errc:   make(chan error, 1),
+	}, nil
+}
+
+// arm sets the timer to first expire at the monotonic time at, and then
+// every interval; an interval of zero makes it one-shot. Times that are not
+// in the future expire immediately.
+func (f *kernelTimer) arm(at Time, interval time.Duration) error {
+	f.schedule(f.sched.set(at, interval))
+	return nil
+}
+
+// schedule sets the runtime timer to expire once, wait from now.
+func (f *kernelTimer) schedule(wait time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.timer.Stop() {
+		// Drain an expiration the goroutine has not taken yet.
+		select {
+		case <-f.timer.C:
+		default:
+		}
+	}
+	f.timer.Reset(wait)
+}
+
+// disarm stops the timer expiring until it is armed again. A wakeup already
+// scheduled is left to find nothing due.
+func (f *kernelTimer) disarm() error {
+	f.sched.clear()
+	return nil
+}
+
+// remaining returns the time until the timer next expires, and false if it is
+// not armed.
+func (f *kernelTimer) remaining() (time.Duration, bool, error) {
+	left, armed := f.sched.remaining()
+	return left, armed, nil
+}
+
+// start runs fire on a new goroutine each time the timer expires, with the
+// number of expirations since the last call, until fire returns false or stop
+// is called. The timer is released when the goroutine exits.
+func (f *kernelTimer) start(fire func(n uint64) bool) {
+	go f.run(fire)
+}
+
+// stop ends the goroutine and releases the timer. Once stop returns fire is
+// not running and will not be called again. stop must not be called from
+// within fire.
+func (f *kernelTimer) stop() {
+	f.stopOnce.Do(func() {
+		close(f.done)
+		<-f.exited
+	})
+}
+
+// release stops the runtime timer. Calling release more than once has no
+// further effect.
+func (f *kernelTimer) release() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.timer.Stop()
+}
+
+func (f *kernelTimer) run(fire func(n uint64) bool) {
+	defer close(f.exited)
+	defer f.release()
+
+	for {
+		select {
+		case <-f.timer.C:
+		case <-f.done:
+			return
+		}
+
+		expired, rearm, wait := f.sched.expired()
+		if rearm {
+			f.schedule(wait)
+		}
+		if expired > 0 && !fire(expired) {
+			return
+		}
+	}
+}
@@ -0,0 +1,21 @@
+//go:build go1.23
+// +build go1.23
+
+package monotime
+
+import "unsafe"
+
+// hchan mirrors the head of the runtime's channel header, up to the queue of
+// blocked receivers. Go 1.23 added the timer field.
+type hchan struct {
+	qcount   uint
+	dataqsiz uint
+	buf      unsafe.Pointer
+	elemsize uint16
+	closed   uint32
+	timer    unsafe.Pointer
+	elemtype unsafe.Pointer
+	sendx    uint
+	recvx    uint
+	recvq    waitq
+}
@@ -0,0 +1,181 @@
+package monotime
+
+import (
+	"math/bits"
+	"sync"
+	"time"
+)
+
+// Each power-of-two range of durations is split into subBuckets linear
+// buckets, so any recorded value is reported within 1/subBuckets (about 6%)
+// of its true value.
+const (
+	subBucketBits = 4
+	subBuckets    = 1 << subBucketBits
+	numBuckets    = (64 - subBucketBits) * subBuckets
+)
+
+// Histogram records a distribution of durations in log-linear buckets, in
+// constant memory. It is safe for concurrent use.
+type Histogram struct {
+	mu     sync.Mutex
+	counts [numBuckets]uint64
+	count  uint64
+	sum    time.Duration
+	min    time.Duration
+	max    time.Duration
+}
+
+// NewHistogram returns an empty Histogram.
+func NewHistogram() *Histogram {
+	return new(Histogram)
+}
+
+// Record adds d to the distribution. Negative durations are recorded as zero.
+func (h *Histogram) Record(d time.Duration) {
+	if d < 0 {
+		d = 0
+	}
+	h.mu.Lock()
+	if h.count == 0 || d < h.min {
+		h.min = d
+	}
+	if d > h.max {
+		h.max = d
+	}
+	h.counts[bucketOf(d)]++
+	h.count++
+	h.sum += d
+	h.mu.Unlock()
+}
+
+// Count returns the number of recorded durations.
+func (h *Histogram) Count() uint64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.count
+}
+
+// Min returns the smallest recorded duration, or 0 if nothing was recorded.
+func (h *Histogram) Min() time.Duration {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.min
+}
+
+// Max returns the largest recorded duration.
+func (h *Histogram) Max() time.Duration {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.max
+}
+
+// Mean returns the arithmetic mean of the recorded durations.
+func (h *Histogram) Mean() time.Duration {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.count == 0 {
+		return 0
+	}
+	return h.sum / time.Duration(h.count)
+}
+
+// Quantile returns an estimate of the q-quantile (0 <= q <= 1) of the
+// recorded durations, e.g. Quantile(0.99) for the 99th percentile.
+func (h *Histogram) Quantile(q float64) time.Duration {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.count == 0 {
+		return 0
+	}
+	if q <= 0 {
+		return h.min
+	}
+	if q >= 1 {
+		return h.max
+	}
+
+	rank := uint64(q*float64(h.count-1)) + 1
+	var seen uint64
+	for i, c := range h.counts {
+		seen += c
+		if seen < rank {
+			continue
+		}
+		lo, hi := bucketBounds(i)
+		d := lo + (hi-lo)/2
+		if d < h.min {
+			d = h.min
+		}
+		if d > h.max {
+			d = h.max
+		}
+		return d
+	}
+	return h.max
+}
+
+// Merge adds every duration recorded in o to h.
+func (h *Histogram) Merge(o *Histogram) {
+	o.mu.Lock()
+	counts, count, sum, min, max := o.counts, o.count, o.sum, o.min, o.max
+	o.mu.Unlock()
+	if count == 0 {
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.count == 0 || min < h.min {
+		h.min = min
+	}
+	if max > h.max {
+		h.max = max
+	}
+	for i, c := range counts {
+		h.counts[i] += c
+	}
+	h.count += count
+	h.sum += sum
+}
+
+// Drain returns a Histogram holding every duration recorded in h and resets
+// h, in one step, so that a duration recorded concurrently is counted in
+// exactly one of them. It suits reporting a distribution interval by
+// interval.
+func (h *Histogram) Drain() *Histogram {
+	d := new(Histogram)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	d.counts, d.count, d.sum, d.min, d.max = h.counts, h.count, h.sum, h.min, h.max
+	h.counts = [numBuckets]uint64{}
+	h.count, h.sum, h.min, h.max = 0, 0, 0, 0
+	return d
+}
+
+// Reset discards every recorded duration.
+func (h *Histogram) Reset() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.counts = [numBuckets]uint64{}
+	h.count, h.sum, h.min, h.max = 0, 0, 0, 0
+}
+
+func bucketOf(d time.Duration) int {
+	v := uint64(d)
+	if v < subBuckets {
+		return int(v)
+	}
+	shift := bits.Len64(v) - 1 - subBucketBits
+	return (shift+1)*subBuckets + int(v>>uint(shift)) - subBuckets
+}
+
+// bucketBounds returns the smallest and largest durations stored in bucket i.
+func bucketBounds(i int) (time.Duration, time.Duration) {
+	if i < subBuckets {
+		return time.Duration(i), time.Duration(i)
+	}
+	shift := uint(i/subBuckets - 1)
+	top := uint64(i%subBuckets + subBuckets)
+	return time.Duration(top << shift), time.Duration((top+1)<<shift - 1)
+}
@@ -0,0 +1,94 @@
+package monotime
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBucketBounds(t *testing.T) {
+	// Every value falls within its bucket's bounds, buckets are in order,
+	// and each is within 1/subBuckets of the values it holds.
+	for _, v := range []time.Duration{0, 1, 15, 16, 17, 31, 32, 1000, 1023, 1024, time.Second, time.Hour, 1<<63 - 1} {
+		i := bucketOf(v)
+		lo, hi := bucketBounds(i)
+		if v < lo || v > hi {
+			t.Errorf("%d in bucket %d, which holds [%d, %d]", v, i, lo, hi)
+		}
+		if width := hi - lo; width > lo/subBuckets+1 && lo > 0 {
+			t.Errorf("bucket %d for %d is %d wide", i, v, width)
+		}
+	}
+	for i := 1; i < numBuckets; i++ {
+		_, prevHi := bucketBounds(i - 1)
+		lo, _ := bucketBounds(i)
+		if lo != prevHi+1 {
+			t.Fatalf("bucket %d starts at %d, bucket %d ends at %d", i, lo, i-1, prevHi)
+		}
+	}
+}
+
+func TestHistogramStats(t *testing.T) {
+	h := NewHistogram()
+	if h.Quantile(0.5) != 0 || h.Mean() != 0 || h.Min() != 0 {
+		t.Error("empty histogram has non-zero statistics")
+	}
+	for i := 1; i <= 1000; i++ {
+		h.Record(time.Duration(i) * time.Microsecond)
+	}
+	h.Record(-time.Second)
+
+	if got := h.Count(); got != 1001 {
+		t.Errorf("Count() = %d, want 1001", got)
+	}
+	if got := h.Min(); got != 0 {
+		t.Errorf("Min() = %v, want 0 for the negative duration", got)
+	}
+	if got := h.Max(); got != time.Millisecond {
+		t.Errorf("Max() = %v, want 1ms", got)
+	}
+	for _, tt := range []struct {
+		q    float64
+		want time.Duration
+	}{
+		{0.5, 500 * time.Microsecond},
+		{0.9, 900 * time.Microsecond},
+		{0.99, 990 * time.Microsecond},
+	} {
+		got := h.Quantile(tt.q)
+		if diff := got - tt.want; diff < -tt.want/subBuckets || diff > tt.want/subBuckets {
+			t.Errorf("Quantile(%v) = %v, want about %v", tt.q, got, tt.want)
+		}
+	}
+	if got := h.Quantile(0); got != 0 {
+		t.Errorf("Quantile(0) = %v, want the minimum", got)
+	}
+	if got := h.Quantile(1); got != time.Millisecond {
+		t.Errorf("Quantile(1) = %v, want the maximum", got)
+	}
+}
+
+func TestHistogramMergeDrain(t *testing.T) {
+	a, b := NewHistogram(), NewHistogram()
+	a.Record(time.Millisecond)
+	b.Record(3 * time.Millisecond)
+	a.Merge(b)
+	if a.Count() != 2 || a.Mean() != 2*time.Millisecond || a.Max() != 3*time.Millisecond {
+		t.Errorf("merged: count %d, mean %v, max %v", a.Count(), a.Mean(), a.Max())
+	}
+
+	d := a.Drain()
+	if d.Count() != 2 || d.Min() != time.Millisecond {
+		t.Errorf("drained: count %d, min %v", d.Count(), d.Min())
+	}
+	if a.Count() != 0 || a.Max() != 0 {
+		t.Errorf("after Drain: count %d, max %v", a.Count(), a.Max())
+	}
+	a.Record(5 * time.Millisecond)
+	if a.Min() != 5*time.Millisecond {
+		t.Errorf("min after Drain = %v, want the new value", a.Min())
+	}
+	a.Reset()
+	if a.Count() != 0 {
+		t.Error("Reset left durations recorded")
+	}
+}
@@ -0,0 +1,54 @@
+package monotime
+
+import (
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// userHZ is the unit of the clock-tick fields in /proc, sysconf(_SC_CLK_TCK).
+// The kernel fixes it at 100 on every architecture Go supports.
+const userHZ = 100
+
+// FromKernelNanos converts a CLOCK_MONOTONIC timestamp in nanoseconds, as
+// produced by bpf_ktime_get_ns or the ftrace "mono" clock, into a Time.
+func FromKernelNanos(ns uint64) Time {
+	return Time(ns)
+}
+
+// ToKernelNanos converts a Time into a CLOCK_MONOTONIC timestamp in
+// nanoseconds, to compare with bpf_ktime_get_ns, as FromKernelNanos's inverse.
+func ToKernelNanos(t Time) uint64 {
+	return uint64(t)
+}
+
+// FromBoottimeNanos converts a CLOCK_BOOTTIME timestamp in nanoseconds, as
+// produced by bpf_ktime_get_boot_ns or the ftrace "boot" clock, into a Time.
+//
+// The conversion subtracts the time the system has spent suspended so far, so
+// it is exact for events since the last resume; events from before a suspend
+// map to a Time that is too late by the length of the later suspends.
+func FromBoottimeNanos(ns uint64) Time {
+	return Time(int64(ns) - int64(suspendOffset()))
+}
+
+// ToBoottimeNanos converts a Time into a CLOCK_BOOTTIME timestamp in
+// nanoseconds, to compare with bpf_ktime_get_boot_ns, as FromBoottimeNanos's
+// inverse. It adds the time spent suspended so far, so it is exact for times
+// since the last resume.
+func ToBoottimeNanos(t Time) uint64 {
+	return uint64(int64(t) + int64(suspendOffset()))
+}
+
+// FromProcTicks converts a time since boot in clock ticks, such as the
+// starttime field of /proc/[pid]/stat, into a Time. Like FromBoottimeNanos,
+// it is exact only for times since the last resume.
+func FromProcTicks(ticks uint64) Time {
+	return FromBoottimeNanos(ticks * uint64(time.Second/userHZ))
+}
+
+// suspendOffset returns how far CLOCK_BOOTTIME is ahead of CLOCK_MONOTONIC:
+// the time the system has spent suspended.
+func suspendOffset() time.Duration {
+	return clockOffset(unix.CLOCK_BOOTTIME)
+}
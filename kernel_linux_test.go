@@ -0,0 +1,60 @@
+package monotime
+
+import (
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+func TestKernelNanos(t *testing.T) {
+	now := Now()
+	if got := FromKernelNanos(ToKernelNanos(now)); got != now {
+		t.Errorf("FromKernelNanos(ToKernelNanos(%d)) = %d", now, got)
+	}
+	// A CLOCK_MONOTONIC reading, as bpf_ktime_get_ns would give, converts to
+	// the present.
+	if d := FromKernelNanos(uint64(readClock(unix.CLOCK_MONOTONIC))).Sub(now); d < 0 || d > time.Second {
+		t.Errorf("kernel timestamp converted to %v from now", d)
+	}
+}
+
+func TestBoottimeNanos(t *testing.T) {
+	now := Now()
+	// A CLOCK_BOOTTIME reading, as bpf_ktime_get_boot_ns would give, converts
+	// to the present.
+	if d := FromBoottimeNanos(uint64(NowBoottime())).Sub(now); d < -time.Millisecond || d > time.Second {
+		t.Errorf("boottime timestamp converted to %v from now", d)
+	}
+	back := FromBoottimeNanos(ToBoottimeNanos(now))
+	if d := back.Sub(now); d < -time.Millisecond || d > time.Millisecond {
+		t.Errorf("FromBoottimeNanos(ToBoottimeNanos(%d)) = %d", now, back)
+	}
+	// Without a suspend the clocks agree, to the noise of reading them.
+	if ToBoottimeNanos(now.Add(time.Millisecond)) < ToKernelNanos(now) {
+		t.Error("boottime timestamp before the monotonic one")
+	}
+}
+
+func TestProcTicks(t *testing.T) {
+	// The starttime field of /proc/self/stat converts to a moment not long
+	// before the test, and not after it.
+	stat, err := ioutil.ReadFile("/proc/self/stat")
+	if err != nil {
+		t.Skip(err)
+	}
+	// The command name, in parentheses, may contain spaces; starttime is
+	// the twentieth field after it.
+	fields := strings.Fields(string(stat[strings.LastIndexByte(string(stat), ')')+1:]))
+	ticks, err := strconv.ParseUint(fields[19], 10, 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	started := FromProcTicks(ticks)
+	if age := Since(started); age < -time.Second/userHZ || age > time.Hour {
+		t.Errorf("process started %v ago, by /proc/self/stat", age)
+	}
+}
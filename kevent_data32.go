@@ -0,0 +1,20 @@
+//go:build (darwin || freebsd) && (386 || arm)
+// +build darwin freebsd
+// +build 386 arm
+
+package monotime
+
+import (
+	"math"
+
+	"golang.org/x/sys/unix"
+)
+
+// setKeventData sets the data of ev, which is 32 bits here. Longer periods
+// are clamped: the timer wakes early, and the schedule re-arms it for the rest.
+func setKeventData(ev *unix.Kevent_t, v int64) {
+	if v > math.MaxInt32 {
+		v = math.MaxInt32
+	}
+	ev.Data = int32(v)
+}
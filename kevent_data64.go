@@ -0,0 +1,11 @@
+//go:build (darwin && !386 && !arm) || dragonfly || (freebsd && !386 && !arm) || netbsd || openbsd
+// +build darwin,!386,!arm dragonfly freebsd,!386,!arm netbsd openbsd
+
+package monotime
+
+import "golang.org/x/sys/unix"
+
+// setKeventData sets the data of ev.
+func setKeventData(ev *unix.Kevent_t, v int64) {
+	ev.Data = v
+}
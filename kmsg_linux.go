@@ -0,0 +1,67 @@
+package monotime
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FromKmsgMicros converts the timestamp of a /dev/kmsg record, microseconds
+// since boot, into a Time.
+//
+// The kernel stamps its log with the scheduler clock, which follows
+// CLOCK_MONOTONIC, so it leaves out time suspended, but is not the same
+// clock: expect the conversion to be off by up to a few milliseconds.
+func FromKmsgMicros(us uint64) Time {
+	return Time(us * uint64(time.Microsecond))
+}
+
+// ToKmsgMicros converts a Time into a /dev/kmsg timestamp, as FromKmsgMicros's
+// inverse.
+func ToKmsgMicros(t Time) uint64 {
+	return uint64(t) / uint64(time.Microsecond)
+}
+
+// ParseKernelLogTimestamp parses the timestamp dmesg and the console print
+// before each kernel message, seconds since boot such as "[   12.345678]",
+// into a Time. The brackets are optional. Like FromKmsgMicros, the result is
+// only as close as the kernel's log clock to CLOCK_MONOTONIC.
+func ParseKernelLogTimestamp(s string) (Time, error) {
+	v := strings.TrimSpace(s)
+	if strings.HasPrefix(v, "[") && strings.HasSuffix(v, "]") {
+		v = strings.TrimSpace(v[1 : len(v)-1])
+	}
+	secs, frac := v, ""
+	if i := strings.IndexByte(v, '.'); i >= 0 {
+		secs, frac = v[:i], v[i+1:]
+	}
+	if len(frac) > 9 {
+		return 0, fmt.Errorf("Error parsing kernel log timestamp %q: more than nanosecond precision", s)
+	}
+	sec, err := strconv.ParseUint(secs, 10, 63)
+	if err != nil {
+		return 0, fmt.Errorf("Error parsing kernel log timestamp %q: %w", s, err)
+	}
+	if sec >= math.MaxInt64/uint64(time.Second) {
+		return 0, fmt.Errorf("Error parsing kernel log timestamp %q: out of range", s)
+	}
+	var ns uint64
+	if frac != "" {
+		if ns, err = strconv.ParseUint(frac, 10, 32); err != nil {
+			return 0, fmt.Errorf("Error parsing kernel log timestamp %q: %w", s, err)
+		}
+		for i := len(frac); i < 9; i++ {
+			ns *= 10
+		}
+	}
+	return Time(sec*uint64(time.Second) + ns), nil
+}
+
+// FormatKernelLogTimestamp formats t the way dmesg does, as seconds since boot
+// to the microsecond in brackets, such as "[   12.345678]".
+func FormatKernelLogTimestamp(t Time) string {
+	us := ToKmsgMicros(t)
+	return fmt.Sprintf("[%5d.%06d]", us/1e6, us%1e6)
+}
@@ -0,0 +1,78 @@
+package monotime
+
+import (
+	"bufio"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseKernelLogTimestamp(t *testing.T) {
+	for _, tt := range []struct {
+		in   string
+		want Time
+	}{
+		{"[   12.345678]", Time(12345678 * time.Microsecond)},
+		{"[12.345678]", Time(12345678 * time.Microsecond)},
+		{"12.5", Time(12500 * time.Millisecond)},
+		{"7", Time(7 * time.Second)},
+		{"[    0.000001]", Time(time.Microsecond)},
+		{"1.000000001", Time(time.Second + 1)},
+	} {
+		got, err := ParseKernelLogTimestamp(tt.in)
+		if err != nil {
+			t.Errorf("ParseKernelLogTimestamp(%q): %v", tt.in, err)
+		} else if got != tt.want {
+			t.Errorf("ParseKernelLogTimestamp(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+	for _, in := range []string{"", "[]", "-1.0", "1.-5", "1.0000000001", "x.1", "[1.5", "99999999999.0"} {
+		if _, err := ParseKernelLogTimestamp(in); err == nil {
+			t.Errorf("ParseKernelLogTimestamp(%q) succeeded", in)
+		}
+	}
+}
+
+func TestFormatKernelLogTimestamp(t *testing.T) {
+	ts := Time(12345678*time.Microsecond + 999)
+	if got, want := FormatKernelLogTimestamp(ts), "[   12.345678]"; got != want {
+		t.Errorf("FormatKernelLogTimestamp = %q, want %q", got, want)
+	}
+	got, err := ParseKernelLogTimestamp(FormatKernelLogTimestamp(ts))
+	if err != nil || got != ts.Truncate(time.Microsecond) {
+		t.Errorf("round trip gave %d, %v", got, err)
+	}
+}
+
+func TestFromKmsgMicros(t *testing.T) {
+	if got := ToKmsgMicros(FromKmsgMicros(1234567)); got != 1234567 {
+		t.Errorf("ToKmsgMicros(FromKmsgMicros(1234567)) = %d", got)
+	}
+
+	// The records in the kernel's log, if the test may read it, come from
+	// before now.
+	f, err := os.OpenFile("/dev/kmsg", os.O_RDONLY, 0)
+	if err != nil {
+		t.Skip(err)
+	}
+	defer f.Close()
+	now := Now()
+	// Read the first record: "priority,sequence,microseconds,flags;text".
+	line, err := bufio.NewReader(f).ReadString('\n')
+	if err != nil {
+		t.Skip(err)
+	}
+	fields := strings.SplitN(strings.SplitN(line, ";", 2)[0], ",", 4)
+	if len(fields) < 3 {
+		t.Fatalf("malformed record %q", line)
+	}
+	us, err := strconv.ParseUint(fields[2], 10, 64)
+	if err != nil {
+		t.Fatalf("malformed record %q: %v", line, err)
+	}
+	if ts := FromKmsgMicros(us); ts > now.Add(10*time.Millisecond) {
+		t.Errorf("kernel log record at %d, after the test started at %d", ts, now)
+	}
+}
@@ -0,0 +1,183 @@
+//go:build darwin || dragonfly || freebsd || netbsd || openbsd
+// +build darwin dragonfly freebsd netbsd openbsd
+
+package monotime
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// kqTimerIdent identifies the timer among the kqueue's events. The wake pipe
+// is watched under its descriptor, with a different filter.
+const kqTimerIdent = 1
+
+// kernelTimer is a kernel timer together with the goroutine waiting on it. It
+// is the engine shared by Ticker and Timer; on Darwin and the BSDs it is a
+// kqueue EVFILT_TIMER, which can only be armed relative to now.
+type kernelTimer struct {
+	kq     int
+	wake   [2]int // pipe written by stop to wake the goroutine
+	fflags uint32 // filter flags selecting the clock, besides the unit
+	sched  relSchedule
+
+	mu     sync.Mutex
+	closed bool // whether the descriptors have been released
+
+	stopOnce sync.Once
+	done     chan struct{}
+	exited   chan struct{}
+	errc     chan error // receives the error that ended the goroutine, if any
+}
+
+// newKernelTimer returns a kernelTimer on the monotonic clock.
+func newKernelTimer() (*kernelTimer, error) {
+	return newKqueueTimer(0, Now)
+}
+
+// newKqueueTimer returns a kernelTimer on the clock that fflags selects and
+// now reads.
+func newKqueueTimer(fflags uint32, now func() Time) (*kernelTimer, error) {
+	kq, err := unix.Kqueue()
+	if err != nil {
+		return nil, fmt.Errorf("Error creating kqueue: %w", err)
+	}
+	unix.CloseOnExec(kq)
+
+	var wake [2]int
+	if err := unix.Pipe(wake[:]); err != nil {
+		unix.Close(kq)
+		return nil, fmt.Errorf("Error creating wake pipe: %w", err)
+	}
+	unix.CloseOnExec(wake[0])
+	unix.CloseOnExec(wake[1])
+
+	var ev unix.Kevent_t
+	unix.SetKevent(&ev, wake[0], unix.EVFILT_READ, unix.EV_ADD)
+	if _, err := unix.Kevent(kq, []unix.Kevent_t{ev}, nil, nil); err != nil {
+		unix.Close(kq)
+		unix.Close(wake[0])
+		unix.Close(wake[1])
+		return nil, fmt.Errorf("Error watching wake pipe: %w", err)
+	}
+
+	return &kernelTimer{
+		kq:     kq,
+		wake:   wake,
+		fflags: fflags,
+		sched:  relSchedule{now: now},
+		done:   make(chan struct{}),
+		exited: make(chan struct{}),
+		errc:   make(chan error, 1),
+	}, nil
+}
+
+// arm sets the timer to first expire at the monotonic time at, and then
+// every interval; an interval of zero makes it one-shot. Times that are not
+// in the future expire immediately.
+func (f *kernelTimer) arm(at Time, interval time.Duration) error {
+	return f.schedule(f.sched.set(at, interval))
+}
+
+// schedule arms the kqueue timer to expire once, wait from now.
+func (f *kernelTimer) schedule(wait time.Duration) error {
+	var ev unix.Kevent_t
+	unix.SetKevent(&ev, kqTimerIdent, unix.EVFILT_TIMER, unix.EV_ADD|unix.EV_ONESHOT)
+	ev.Fflags = kqTimerFflags | f.fflags
+	// Round up, so the timer never fires before it is due.
+	setKeventData(&ev, int64((wait+kqTimerUnit-1)/kqTimerUnit))
+	if _, err := unix.Kevent(f.kq, []unix.Kevent_t{ev}, nil, nil); err != nil {
+		return fmt.Errorf("Error arming kqueue timer: %w", err)
+	}
+	return nil
+}
+
+// disarm stops the timer expiring until it is armed again. A wakeup already
+// scheduled is left to find nothing due.
+func (f *kernelTimer) disarm() error {
+	f.sched.clear()
+	return nil
+}
+
+// remaining returns the time until the timer next expires, and false if it is
+// not armed.
+func (f *kernelTimer) remaining() (time.Duration, bool, error) {
+	left, armed := f.sched.remaining()
+	return left, armed, nil
+}
+
+// start runs fire on a new goroutine each time the timer expires, with the
+// number of expirations since the last call, until fire returns false or stop
+// is called. The timer is released when the goroutine exits, so one-shot
+// timers don't hold their descriptors until stopped.
+func (f *kernelTimer) start(fire func(n uint64) bool) {
+	go f.run(fire)
+}
+
+// stop ends the goroutine and releases the timer. Once stop returns fire is
+// not running and will not be called again. stop must not be called from
+// within fire.
+func (f *kernelTimer) stop() {
+	f.stopOnce.Do(func() {
+		close(f.done)
+		f.mu.Lock()
+		if !f.closed {
+			unix.Write(f.wake[1], []byte{1})
+		}
+		f.mu.Unlock()
+		<-f.exited
+	})
+}
+
+// release closes the descriptors of a timer whose goroutine is not running.
+// Only once the goroutine is gone can they be closed, so their numbers can't
+// be reused by another file while it still waits on them. Calling release
+// more than once has no further effect.
+func (f *kernelTimer) release() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.closed {
+		return
+	}
+	f.closed = true
+	unix.Close(f.kq)
+	unix.Close(f.wake[0])
+	unix.Close(f.wake[1])
+}
+
+func (f *kernelTimer) run(fire func(n uint64) bool) {
+	defer close(f.exited)
+	defer f.release()
+
+	events := make([]unix.Kevent_t, 2)
+	for {
+		n, err := unix.Kevent(f.kq, nil, events, nil)
+		if errors.Is(err, unix.EINTR) {
+			continue
+		}
+		if err != nil {
+			f.fail(fmt.Errorf("Error waiting on kqueue: %w", err))
+			return
+		}
+
+		for _, ev := range events[:n] {
+			if ev.Filter == unix.EVFILT_READ {
+				return
+			}
+		}
+		expired, rearm, wait := f.sched.expired()
+		if rearm {
+			if err := f.schedule(wait); err != nil {
+				f.fail(err)
+				return
+			}
+		}
+		if expired > 0 && !fire(expired) {
+			return
+		}
+	}
+}
@@ -0,0 +1,12 @@
+//go:build dragonfly || netbsd || openbsd
+// +build dragonfly netbsd openbsd
+
+package monotime
+
+import "time"
+
+// The other BSDs only take kqueue timer periods in milliseconds.
+const (
+	kqTimerFflags = 0
+	kqTimerUnit   = time.Millisecond
+)
@@ -0,0 +1,16 @@
+//go:build darwin || freebsd
+// +build darwin freebsd
+
+package monotime
+
+import (
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// Darwin and FreeBSD take kqueue timer periods in nanoseconds on request.
+const (
+	kqTimerFflags = unix.NOTE_NSECONDS
+	kqTimerUnit   = time.Nanosecond
+)
@@ -0,0 +1,147 @@
+// Package loadgen drives a callback at a precise target rate.
+//
+// Scheduling is open-loop: operations are started on a fixed monotonic
+// schedule regardless of how long earlier operations take, and latency is
+// measured from when each operation was due rather than from when it actually
+// started. A slow system under test therefore shows up as growing latency
+// instead of being masked by a load generator that politely waits for it.
+package loadgen
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/thisguycodes/monotime"
+)
+
+// Config describes the load to generate.
+type Config struct {
+	// Rate is the target number of operations started per second.
+	Rate float64
+
+	// Duration is how long to generate load for. Operations still in
+	// flight when it elapses are waited for.
+	Duration time.Duration
+
+	// Concurrency bounds the number of operations in flight. Zero means
+	// unbounded. When the bound is reached, due operations are started
+	// as soon as a slot frees up and the wait counts towards their latency.
+	Concurrency int
+}
+
+// Result summarizes a run.
+type Result struct {
+	// Started and Failed count the operations started and the ones whose
+	// callback returned an error.
+	Started, Failed uint64
+
+	// Elapsed is the time from the first scheduled operation until the
+	// last one finished.
+	Elapsed time.Duration
+
+	// Latency is measured from when each operation was due until its
+	// callback returned, including any time spent waiting to start.
+	Latency *monotime.Histogram
+
+	// Service is measured from when each callback was invoked until it
+	// returned.
+	Service *monotime.Histogram
+
+	// Lag is how late each operation was started relative to schedule.
+	Lag *monotime.Histogram
+}
+
+// Run calls fn at cfg.Rate for cfg.Duration, or until ctx is done, and
+// reports the latencies observed. The context passed to fn is ctx.
+func Run(ctx context.Context, cfg Config, fn func(context.Context) error) (*Result, error) {
+	if cfg.Rate <= 0 {
+		return nil, errors.New("loadgen: rate must be positive")
+	}
+	if cfg.Duration <= 0 {
+		return nil, errors.New("loadgen: duration must be positive")
+	}
+	interval := time.Duration(float64(time.Second) / cfg.Rate)
+	if interval <= 0 {
+		return nil, errors.New("loadgen: rate too high")
+	}
+
+	res := &Result{
+		Latency: monotime.NewHistogram(),
+		Service: monotime.NewHistogram(),
+		Lag:     monotime.NewHistogram(),
+	}
+
+	var slots chan struct{}
+	if cfg.Concurrency > 0 {
+		slots = make(chan struct{}, cfg.Concurrency)
+	}
+
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		failed uint64
+	)
+
+	pacer := monotime.NewPacer(interval)
+	start := pacer.Next()
+	end := start.Add(cfg.Duration)
+	for due := start; due.Before(end); due = pacer.Next() {
+		if !waitUntil(ctx, due) {
+			break
+		}
+		if slots != nil {
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+			}
+			if ctx.Err() != nil {
+				break
+			}
+		}
+
+		began := monotime.Now()
+		res.Lag.Record(began.Sub(due))
+		res.Started++
+		wg.Add(1)
+		go func(due, began monotime.Time) {
+			defer wg.Done()
+			err := fn(ctx)
+			done := monotime.Now()
+			res.Service.Record(done.Sub(began))
+			res.Latency.Record(done.Sub(due))
+			if err != nil {
+				mu.Lock()
+				failed++
+				mu.Unlock()
+			}
+			if slots != nil {
+				<-slots
+			}
+		}(due, began)
+	}
+	wg.Wait()
+
+	res.Failed = failed
+	res.Elapsed = monotime.Since(start)
+	return res, ctx.Err()
+}
+
+// waitUntil sleeps until t, reporting false if ctx is done first.
+func waitUntil(ctx context.Context, t monotime.Time) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if !monotime.Now().Before(t) {
+		return true
+	}
+	timer := monotime.NewTimerAt(t)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,48 @@
+package loadgen
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/thisguycodes/monotime"
+)
+
+func TestRun(t *testing.T) {
+	var calls uint64
+	cfg := Config{Rate: 1000, Duration: 50 * time.Millisecond}
+	start := monotime.Now()
+	res, err := Run(context.Background(), cfg, func(context.Context) error {
+		atomic.AddUint64(&calls, 1)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := atomic.LoadUint64(&calls); res.Started != n || n != 50 {
+		t.Errorf("started %d operations with %d calls, want 50", res.Started, n)
+	}
+	// The run ends at the last operation due, not an interval past it.
+	if elapsed := monotime.Since(start); elapsed >= cfg.Duration+20*time.Millisecond {
+		t.Errorf("run of %v took %v", cfg.Duration, elapsed)
+	}
+}
+
+func TestRunCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+	start := monotime.Now()
+	// One operation is due at once, and the next only after the run is
+	// cancelled.
+	res, err := Run(ctx, Config{Rate: 1, Duration: time.Hour}, func(context.Context) error { return nil })
+	if err != context.Canceled {
+		t.Errorf("Run() = %v, want context.Canceled", err)
+	}
+	if elapsed := monotime.Since(start); elapsed > time.Second {
+		t.Errorf("cancelled run returned after %v", elapsed)
+	}
+	if res.Started != 1 {
+		t.Errorf("started %d operations, want 1", res.Started)
+	}
+}
@@ -0,0 +1,130 @@
+package monotime
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrOtherBoot is the error, wrapped, for decoding a Time saved under a
+// different boot, or on a different host, whose monotonic clock it isn't a
+// reading of.
+var ErrOtherBoot = errors.New("monotonic time is from another boot")
+
+// jsonTime is the JSON form of a Time: its nanoseconds, and the boot ID they
+// were read under, if the system has one.
+type jsonTime struct {
+	Nanos int64  `json:"ns"`
+	Boot  string `json:"boot,omitempty"`
+}
+
+// MarshalJSON encodes t as an object holding its nanoseconds and the current
+// boot ID, such as {"ns":8123456789012,"boot":"…"}, so that UnmarshalJSON can
+// tell whether it still means anything.
+func (t Time) MarshalJSON() ([]byte, error) {
+	boot, _ := BootID()
+	return json.Marshal(jsonTime{Nanos: int64(t), Boot: boot})
+}
+
+// UnmarshalJSON decodes a Time encoded by MarshalJSON. It fails with
+// ErrOtherBoot if the Time was saved under a boot ID other than the current
+// one, including none, since its nanoseconds would measure from somewhere
+// else. On systems without a boot ID, such as Windows, nothing can be
+// checked, and any Time is accepted. A bare JSON number decodes as a Time
+// without a boot ID. Like json.Unmarshal, UnmarshalJSON leaves t unchanged
+// for null.
+func (t *Time) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	boot, _ := BootID()
+	v, err := decodeTime(data, boot)
+	if err != nil {
+		return err
+	}
+	*t = v
+	return nil
+}
+
+// decodeTime decodes a Time encoded by MarshalJSON, checking it was saved
+// under boot ID boot unless boot is empty.
+func decodeTime(data []byte, boot string) (Time, error) {
+	var v jsonTime
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] != '{' {
+		n, err := strconv.ParseInt(string(data), 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("Error decoding Time: %w", err)
+		}
+		v.Nanos = n
+	} else if err := json.Unmarshal(data, &v); err != nil {
+		return 0, fmt.Errorf("Error decoding Time: %w", err)
+	}
+	if boot != "" && v.Boot != boot {
+		return 0, fmt.Errorf("Error decoding Time from boot %q: %w", v.Boot, ErrOtherBoot)
+	}
+	return Time(v.Nanos), nil
+}
+
+// MarshalText encodes t as String does, such as "T+8123.456789012s".
+// Unlike the JSON form it carries no boot ID, so it suits logs and map keys
+// rather than Times that outlive the process.
+func (t Time) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+// UnmarshalText decodes a Time in the form String writes. The seconds may
+// have fewer than nine decimal places, or none.
+func (t *Time) UnmarshalText(text []byte) error {
+	v, err := parseReading("T", string(text))
+	if err != nil {
+		return err
+	}
+	*t = Time(v)
+	return nil
+}
+
+// parseReading parses a clock reading formatted by formatReading.
+func parseReading(clock, s string) (int64, error) {
+	v := strings.TrimPrefix(s, clock)
+	if len(v) == len(s) || len(v) < 2 || (v[0] != '+' && v[0] != '-') || !strings.HasSuffix(v, "s") {
+		return 0, fmt.Errorf("Error parsing time %q: want the form %s+1.5s", s, clock)
+	}
+	neg := v[0] == '-'
+	secs, frac := v[1:len(v)-1], ""
+	if i := strings.IndexByte(secs, '.'); i >= 0 {
+		secs, frac = secs[:i], secs[i+1:]
+		if frac == "" || len(frac) > 9 {
+			return 0, fmt.Errorf("Error parsing time %q: want one to nine decimal places", s)
+		}
+	}
+	sec, err := strconv.ParseUint(secs, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("Error parsing time %q: %w", s, err)
+	}
+	var ns uint64
+	if frac != "" {
+		if ns, err = strconv.ParseUint(frac, 10, 32); err != nil {
+			return 0, fmt.Errorf("Error parsing time %q: %w", s, err)
+		}
+		for i := len(frac); i < 9; i++ {
+			ns *= 10
+		}
+	}
+	// The magnitude may reach 1<<63 only when negative.
+	limit := uint64(1<<63 - 1)
+	if neg {
+		limit++
+	}
+	if sec > limit/1e9 || sec*1e9 > limit-ns {
+		return 0, fmt.Errorf("Error parsing time %q: out of range", s)
+	}
+	u := sec*1e9 + ns
+	if neg {
+		return int64(-u), nil
+	}
+	return int64(u), nil
+}
@@ -0,0 +1,113 @@
+package monotime
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestTimeJSON(t *testing.T) {
+	now := Now()
+	b, err := json.Marshal(struct{ At Time }{now})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got struct{ At Time }
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatalf("decoding %s: %v", b, err)
+	}
+	if got.At != now {
+		t.Errorf("decoded %s as %d, want %d", b, got.At, now)
+	}
+
+	got.At = now
+	if err := json.Unmarshal([]byte(`{"At":null}`), &got); err != nil || got.At != now {
+		t.Errorf("decoding null gave %d, %v; want it unchanged", got.At, err)
+	}
+}
+
+func TestTimeJSONOtherBoot(t *testing.T) {
+	boot, err := BootID()
+	if err != nil || boot == "" {
+		t.Skip("no boot ID to check against")
+	}
+	for _, data := range []string{`{"ns":5,"boot":"other"}`, `{"ns":5}`, `5`} {
+		var at Time
+		if err := json.Unmarshal([]byte(data), &at); !errors.Is(err, ErrOtherBoot) {
+			t.Errorf("decoding %s: got %v, want ErrOtherBoot", data, err)
+		}
+	}
+	var at Time
+	if err := json.Unmarshal([]byte(fmt.Sprintf(`{"ns":5,"boot":%q}`, boot)), &at); err != nil || at != 5 {
+		t.Errorf("decoding a Time from this boot gave %d, %v", at, err)
+	}
+}
+
+func TestDecodeTime(t *testing.T) {
+	// Without a boot ID to check against, anything goes.
+	for _, data := range []string{`{"ns":5,"boot":"other"}`, `{"ns":5}`, ` 5 `} {
+		if got, err := decodeTime([]byte(data), ""); err != nil || got != 5 {
+			t.Errorf("decodeTime(%s) = %d, %v; want 5", data, got, err)
+		}
+	}
+	for _, data := range []string{`"5"`, `{"ns":"5"}`, `5.5`, ``} {
+		if _, err := decodeTime([]byte(data), ""); err == nil || errors.Is(err, ErrOtherBoot) {
+			t.Errorf("decodeTime(%s): got %v, want a syntax error", data, err)
+		}
+	}
+}
+
+func TestTimeText(t *testing.T) {
+	for _, at := range []Time{0, 1, Now(), -1500 * Time(time.Millisecond), math.MaxInt64, math.MinInt64} {
+		text, err := at.MarshalText()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got Time
+		if err := got.UnmarshalText(text); err != nil || got != at {
+			t.Errorf("UnmarshalText(%q) = %d, %v; want %d", text, got, err, at)
+		}
+	}
+
+	// As a map key the text form is used.
+	b, err := json.Marshal(map[Time]int{Time(time.Second): 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"T+1.000000000s":1}`; string(b) != want {
+		t.Errorf("map encoded as %s, want %s", b, want)
+	}
+	var m map[Time]int
+	if err := json.Unmarshal(b, &m); err != nil || m[Time(time.Second)] != 1 {
+		t.Errorf("map decoded as %v, %v", m, err)
+	}
+}
+
+func TestTimeUnmarshalText(t *testing.T) {
+	for _, tt := range []struct {
+		in   string
+		want Time
+	}{
+		{"T+12.5s", Time(12500 * time.Millisecond)},
+		{"T+7s", Time(7 * time.Second)},
+		{"T-0.000000001s", -1},
+	} {
+		var got Time
+		if err := got.UnmarshalText([]byte(tt.in)); err != nil || got != tt.want {
+			t.Errorf("UnmarshalText(%q) = %d, %v; want %d", tt.in, got, err, tt.want)
+		}
+	}
+	for _, in := range []string{
+		"", "12.5s", "T12.5s", "monotonic+12.5s", "T+12.5", "raw+1s", "T+s",
+		"T+1.s", "T+1.0000000001s", "T++1s", "T+1.-5s",
+		"T+9223372036.854775808s", "T-9223372036.854775809s", "T+99999999999s",
+	} {
+		var got Time
+		if err := got.UnmarshalText([]byte(in)); err == nil {
+			t.Errorf("UnmarshalText(%q) = %d, want an error", in, got)
+		}
+	}
+}
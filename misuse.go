@@ -0,0 +1,134 @@
+package monotime
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unsafe"
+)
+
+// MisuseError describes an incorrect use of the package's API detected in
+// checked mode. It is the value checked mode panics with when no handler is
+// installed.
+type MisuseError struct {
+	// Op is the method that was misused, e.g. "TimeTicker.Reset".
+	Op string
+	// Problem says what was wrong.
+	Problem string
+	// Stack is the stack trace of the goroutine that misused the API.
+	Stack []byte
+}
+
+func (e *MisuseError) Error() string {
+	return fmt.Sprintf("%s: %s\n%s", e.Op, e.Problem, e.Stack)
+}
+
+// staleReadGrace is how long checked mode lets a stopped Ticker's receivers
+// notice the Stop before treating one still waiting on C as a bug.
+const staleReadGrace = time.Second
+
+var (
+	checked int32
+
+	checkMu      sync.Mutex
+	checkHandler func(*MisuseError)
+)
+
+func init() {
+	if os.Getenv("MONOTIME_CHECKS") == "1" {
+		EnableChecks(nil)
+	}
+}
+
+// EnableChecks turns on checked mode, in the spirit of the race detector: the
+// package watches for misuse of its timers at runtime and reports each case,
+// with a stack trace, to handler. A nil handler panics with a *MisuseError
+// instead. Checked mode detects
+//
+//   - copying a Ticker, Timer or TimeTicker and using the copy,
+//   - calling TimeTicker.Reset after Stop,
+//   - concurrent calls to TimeTicker.Reset,
+//   - receiving from a Ticker's C after Stop: a goroutine still waiting on C
+//     a second after Stop is reported, and left waiting.
+//
+// Checked mode costs a little on every call, so leave it off in production.
+// Setting MONOTIME_CHECKS=1 in the environment enables it with a nil handler
+// at startup.
+func EnableChecks(handler func(*MisuseError)) {
+	checkMu.Lock()
+	checkHandler = handler
+	checkMu.Unlock()
+	atomic.StoreInt32(&checked, 1)
+}
+
+// DisableChecks turns checked mode off.
+func DisableChecks() {
+	atomic.StoreInt32(&checked, 0)
+}
+
+func checksEnabled() bool {
+	return atomic.LoadInt32(&checked) != 0
+}
+
+// reportMisuse reports a misuse of op to the checked mode handler, capturing
+// the stack of the calling goroutine.
+func reportMisuse(op, problem string) {
+	buf := make([]byte, 64<<10)
+	buf = buf[:runtime.Stack(buf, false)]
+	reportMisuseStack(op, problem, buf)
+}
+
+func reportMisuseStack(op, problem string, stack []byte) {
+	checkMu.Lock()
+	handler := checkHandler
+	checkMu.Unlock()
+
+	err := &MisuseError{Op: op, Problem: problem, Stack: stack}
+	if handler == nil {
+		panic(err)
+	}
+	handler(err)
+}
+
+// copyCheck detects copies of the value it is embedded in, by remembering the
+// address it was initialized at.
+type copyCheck uintptr
+
+func (c *copyCheck) init() {
+	*c = copyCheck(unsafe.Pointer(c))
+}
+
+// check reports op as misuse if the value holding c has been copied since
+// init.
+func (c *copyCheck) check(op string) {
+	if checksEnabled() && uintptr(*c) != uintptr(unsafe.Pointer(c)) {
+		reportMisuse(op, "called on a copy; timers must not be copied after creation")
+	}
+}
+
+// watchStaleReads reports a goroutine that is still receiving from a stopped
+// Ticker's channel once staleReadGrace has passed; stack is where Stop was
+// called. A receiver that is still waiting would otherwise hang forever. The
+// channel is only inspected, never sent on, so the receiver is left blocked.
+func watchStaleReads(op string, c chan Time, stack []byte) {
+	time.Sleep(staleReadGrace)
+	if hasReceivers(c) {
+		reportMisuseStack(op, "received from after Stop; a goroutine is still blocked on the channel. Stop was called at:", stack)
+	}
+}
+
+// waitq mirrors the runtime's list of goroutines blocked on a channel.
+type waitq struct {
+	first unsafe.Pointer
+	last  unsafe.Pointer
+}
+
+// hasReceivers reports whether a goroutine is blocked receiving from c,
+// reading the runtime's channel header without taking its lock.
+func hasReceivers(c chan Time) bool {
+	h := *(**hchan)(unsafe.Pointer(&c))
+	return atomic.LoadPointer(&h.recvq.first) != nil
+}
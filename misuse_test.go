@@ -0,0 +1,84 @@
+package monotime
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// collectMisuse enables checked mode for the rest of the test, and returns a
+// channel receiving each misuse reported.
+func collectMisuse(t *testing.T) <-chan *MisuseError {
+	reports := make(chan *MisuseError, 10)
+	EnableChecks(func(err *MisuseError) { reports <- err })
+	t.Cleanup(DisableChecks)
+	return reports
+}
+
+func TestChecksCopiedTicker(t *testing.T) {
+	reports := collectMisuse(t)
+	ticker := NewTicker(time.Hour)
+	defer ticker.Stop()
+	copied := *ticker
+	copied.Stop()
+	select {
+	case err := <-reports:
+		if err.Op != "Ticker.Stop" || len(err.Stack) == 0 {
+			t.Errorf("reported %q with %d bytes of stack, want Ticker.Stop with its stack", err.Op, len(err.Stack))
+		}
+	default:
+		t.Fatal("Stop on a copied Ticker was not reported")
+	}
+}
+
+func TestChecksStaleRead(t *testing.T) {
+	reports := collectMisuse(t)
+	ticker := NewTicker(time.Hour)
+	received := make(chan struct{})
+	go func() {
+		<-ticker.C
+		close(received)
+	}()
+	time.Sleep(10 * time.Millisecond)
+	ticker.Stop()
+
+	select {
+	case err := <-reports:
+		if err.Op != "Ticker.C" || !strings.Contains(err.Problem, "after Stop") {
+			t.Errorf("reported %s: %s; want a stale read of Ticker.C", err.Op, err.Problem)
+		}
+	case <-time.After(staleReadGrace + 5*time.Second):
+		t.Fatal("receiver blocked after Stop was not reported")
+	}
+	// The receiver is reported, not handed a tick.
+	select {
+	case <-received:
+		t.Error("blocked receiver was sent a value")
+	default:
+	}
+}
+
+func TestChecksStopWithoutReceivers(t *testing.T) {
+	reports := collectMisuse(t)
+	ticker := NewTicker(time.Hour)
+	ticker.Stop()
+	select {
+	case err := <-reports:
+		t.Errorf("Stop with no receivers reported %s: %s", err.Op, err.Problem)
+	case <-time.After(staleReadGrace + 100*time.Millisecond):
+	}
+}
+
+func TestChecksNilHandlerPanics(t *testing.T) {
+	EnableChecks(nil)
+	defer DisableChecks()
+	defer func() {
+		if _, ok := recover().(*MisuseError); !ok {
+			t.Error("misuse with no handler did not panic with a *MisuseError")
+		}
+	}()
+	ticker := NewTicker(time.Hour)
+	defer ticker.Stop()
+	copied := *ticker
+	copied.Stop()
+}
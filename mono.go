@@ -1,27 +1,186 @@
 package monotime
 
 import (
-	"fmt"
+	"math"
+	"strconv"
+	"sync/atomic"
 	"time"
-
-	"golang.org/x/sys/unix"
 )
 
 // Time is a monotonic timestamp, measured as nanoseconds since some
 // arbitrary time chosen by the system at boot.
+//
+// The zero Time stands for an unset timestamp: no clock reads it once the
+// system is up, so APIs taking an optional Time treat the zero value as
+// absent. Use IsZero or IsSet to test for it.
 type Time int64
 
-// Add returns the monotonic time t+d.
+// Add returns the monotonic time t+d. Like integer addition it wraps on
+// overflow, so a far enough future turns into the distant past; use AddSat
+// for deadlines that may be arbitrarily far off.
 func (t Time) Add(d time.Duration) Time {
 	return t + Time(d)
 }
 
+// AddChecked returns the monotonic time t+d, and whether it is exact: false
+// if it overflowed, in which case the Time is as from Add.
+func (t Time) AddChecked(d time.Duration) (Time, bool) {
+	sum := t + Time(d)
+	return sum, (sum > t) == (d > 0) || d == 0
+}
+
+// AddSat returns the monotonic time t+d, saturating at the latest or earliest
+// representable Time instead of overflowing.
+func (t Time) AddSat(d time.Duration) Time {
+	sum, ok := t.AddChecked(d)
+	if ok {
+		return sum
+	}
+	if d > 0 {
+		return math.MaxInt64
+	}
+	return math.MinInt64
+}
+
 // Sub returns the monotonic duration t-u. To compute t-d for a duration d,
 // use t.Add(-d).
 func (t Time) Sub(tt Time) time.Duration {
 	return time.Duration(t - tt)
 }
 
+// IsZero reports whether t is the zero Time, an unset timestamp.
+func (t Time) IsZero() bool {
+	return t == 0
+}
+
+// IsSet reports whether t is a timestamp rather than the zero Time.
+func (t Time) IsSet() bool {
+	return t != 0
+}
+
+// Before reports whether t is before u.
+func (t Time) Before(u Time) bool {
+	return t < u
+}
+
+// After reports whether t is after u.
+func (t Time) After(u Time) bool {
+	return t > u
+}
+
+// Equal reports whether t and u are the same instant.
+func (t Time) Equal(u Time) bool {
+	return t == u
+}
+
+// Compare compares t with u. It returns -1 if t is before u, +1 if t is after
+// u, and 0 if they are equal.
+func (t Time) Compare(u Time) int {
+	switch {
+	case t < u:
+		return -1
+	case t > u:
+		return +1
+	}
+	return 0
+}
+
+// Min returns the earlier of a and b.
+func Min(a, b Time) Time {
+	if b.Before(a) {
+		return b
+	}
+	return a
+}
+
+// Max returns the later of a and b.
+func Max(a, b Time) Time {
+	if b.After(a) {
+		return b
+	}
+	return a
+}
+
+// Clamp returns t limited to the range lo to hi. If hi is before lo, Clamp
+// returns lo.
+func (t Time) Clamp(lo, hi Time) Time {
+	return Max(lo, Min(t, hi))
+}
+
+// Since returns the time elapsed since t on the monotonic clock. It is
+// shorthand for Now().Sub(t).
+func Since(t Time) time.Duration {
+	return Now().Sub(t)
+}
+
+// Until returns the time left until t on the monotonic clock, negative once
+// t has passed. It is shorthand for t.Sub(Now()).
+func Until(t Time) time.Duration {
+	return t.Sub(Now())
+}
+
+// SubSat returns the duration t-u, saturating at the longest or most negative
+// time.Duration instead of overflowing.
+func (t Time) SubSat(u Time) time.Duration {
+	diff := t - u
+	if (diff < t) != (u > 0) && u != 0 {
+		if u > 0 {
+			return minDuration
+		}
+		return maxDuration
+	}
+	return time.Duration(diff)
+}
+
+// mulSat returns n*d, saturating instead of overflowing.
+func mulSat(n int64, d time.Duration) time.Duration {
+	if n == 0 || d == 0 {
+		return 0
+	}
+	p := time.Duration(n) * d
+	// The division misses only MinInt64 * -1, which wraps back to
+	// MinInt64.
+	if p/d == time.Duration(n) && !(d == -1 && n == math.MinInt64) {
+		return p
+	}
+	if (n > 0) == (d > 0) {
+		return maxDuration
+	}
+	return minDuration
+}
+
+// String returns t as the offset from its clock's origin in seconds, such as
+// "T+8123.456789012s". A Time doesn't record which clock it was read from, so
+// the prefix names none: a reading of NowBoottime looks the same as one of
+// Now.
+func (t Time) String() string {
+	return formatReading("T", int64(t))
+}
+
+// formatReading formats the clock reading ns, labelled with its clock, as a
+// signed number of seconds to the nanosecond.
+func formatReading(clock string, ns int64) string {
+	b := make([]byte, 0, len(clock)+22)
+	b = append(b, clock...)
+	// Convert before negating, which would overflow for MinInt64.
+	u := uint64(ns)
+	if ns < 0 {
+		b = append(b, '-')
+		u = -u
+	} else {
+		b = append(b, '+')
+	}
+	b = strconv.AppendUint(b, u/1e9, 10)
+	b = append(b, '.')
+	frac := strconv.AppendUint(nil, u%1e9, 10)
+	for i := len(frac); i < 9; i++ {
+		b = append(b, '0')
+	}
+	b = append(b, frac...)
+	b = append(b, 's')
+	return string(b)
+}
+
 // Round returns the result of routing t to the nearest multiple of d (since
 // the zero time). The rounding behavior for halfway values is to round up. If
 // d <= 0, Round returns t unchanged.
@@ -59,12 +218,32 @@ func (t Time) Truncate(d time.Duration) Time {
 // Now gets the current monotonic time
 //
 // Monotonic time is *not comparable* accross sytems, or even reboots.
+//
+// Now panics if the kernel fails to read the clock; see NowErr.
 func Now() Time {
-	spec := new(unix.Timespec)
-	err := unix.ClockGettime(unix.CLOCK_MONOTONIC, spec)
+	t, err := NowErr()
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// NowErr is like Now, but returns an error instead of panicking if the kernel
+// fails to read the clock. That takes a broken seccomp policy or sandbox, so
+// few programs need it, but a daemon that must not crash can run on NowErr
+// and fall back on time.Now.
+func NowErr() (Time, error) {
+	if atomic.LoadInt32(&strict) != 0 {
+		return strictNow()
+	}
+	return nowErr()
+}
+
+// now is Now without strict-monotonicity checking.
+func now() Time {
+	t, err := nowErr()
 	if err != nil {
-		err = fmt.Errorf("Error getting monotime from the kernel: %w", err)
 		panic(err)
 	}
-	return Time(spec.Nano())
+	return t
 }
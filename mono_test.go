@@ -0,0 +1,218 @@
+package monotime
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestNowErr(t *testing.T) {
+	before := Now()
+	got, err := NowErr()
+	after := Now()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got < before || got > after {
+		t.Errorf("NowErr() = %d, not between %d and %d", got, before, after)
+	}
+}
+
+func TestNowErrStrict(t *testing.T) {
+	EnableStrict(func(prev, now Time) {
+		t.Errorf("clock went backwards from %d to %d", prev, now)
+	})
+	defer DisableStrict()
+
+	prev := Now()
+	for i := 0; i < 1000; i++ {
+		got, err := NowErr()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got < prev {
+			t.Fatalf("NowErr() = %d after %d", got, prev)
+		}
+		prev = got
+	}
+}
+
+func TestSince(t *testing.T) {
+	start := Now()
+	time.Sleep(10 * time.Millisecond)
+	before := Now().Sub(start)
+	got := Since(start)
+	after := Now().Sub(start)
+	if got < before || got > after {
+		t.Errorf("Since() = %v, not between %v and %v", got, before, after)
+	}
+	if got < 10*time.Millisecond {
+		t.Errorf("Since() = %v after sleeping 10ms", got)
+	}
+}
+
+func TestUntil(t *testing.T) {
+	deadline := Now().Add(time.Second)
+	before := deadline.Sub(Now())
+	got := Until(deadline)
+	after := deadline.Sub(Now())
+	if got > before || got < after {
+		t.Errorf("Until() = %v, not between %v and %v", got, after, before)
+	}
+	if past := Until(Now().Add(-time.Second)); past > -time.Second {
+		t.Errorf("Until() a second ago = %v, want at most -1s", past)
+	}
+}
+
+func TestAddSat(t *testing.T) {
+	const max, min = Time(math.MaxInt64), Time(math.MinInt64)
+	for _, tt := range []struct {
+		t    Time
+		d    time.Duration
+		want Time
+		ok   bool
+	}{
+		{1, 2, 3, true},
+		{1, -2, -1, true},
+		{max, 0, max, true},
+		{max - 1, 1, max, true},
+		{max, 1, max, false},
+		{max / 2, math.MaxInt64, max, false},
+		{min + 1, -1, min, true},
+		{min, -1, min, false},
+		{-1, math.MinInt64, min, false},
+	} {
+		if got := tt.t.AddSat(tt.d); got != tt.want {
+			t.Errorf("%d.AddSat(%d) = %d, want %d", tt.t, tt.d, got, tt.want)
+		}
+		got, ok := tt.t.AddChecked(tt.d)
+		if ok != tt.ok || got != tt.t.Add(tt.d) {
+			t.Errorf("%d.AddChecked(%d) = %d, %v; want %d, %v", tt.t, tt.d, got, ok, tt.t.Add(tt.d), tt.ok)
+		}
+	}
+}
+
+func TestSubSat(t *testing.T) {
+	const max, min = Time(math.MaxInt64), Time(math.MinInt64)
+	for _, tt := range []struct {
+		t, u Time
+		want time.Duration
+	}{
+		{3, 1, 2},
+		{1, 3, -2},
+		{max, 0, math.MaxInt64},
+		{max, -1, math.MaxInt64},
+		{max, min, math.MaxInt64},
+		{min, 1, math.MinInt64},
+		{min, max, math.MinInt64},
+		{-1, max, math.MinInt64},
+		{min, min, 0},
+	} {
+		if got := tt.t.SubSat(tt.u); got != tt.want {
+			t.Errorf("%d.SubSat(%d) = %d, want %d", tt.t, tt.u, got, tt.want)
+		}
+	}
+}
+
+func TestMulSat(t *testing.T) {
+	for _, tt := range []struct {
+		n    int64
+		d    time.Duration
+		want time.Duration
+	}{
+		{0, math.MaxInt64, 0},
+		{3, 2, 6},
+		{-3, 2, -6},
+		{math.MaxInt64, 2, math.MaxInt64},
+		{math.MaxInt64, -2, math.MinInt64},
+		{-1, math.MinInt64, math.MaxInt64},
+		{math.MinInt64, -1, math.MaxInt64},
+		{math.MinInt64, 1, math.MinInt64},
+	} {
+		if got := mulSat(tt.n, tt.d); got != tt.want {
+			t.Errorf("mulSat(%d, %d) = %d, want %d", tt.n, tt.d, got, tt.want)
+		}
+	}
+}
+
+func TestTimeString(t *testing.T) {
+	for _, tt := range []struct {
+		t    Time
+		want string
+	}{
+		{0, "T+0.000000000s"},
+		{Time(8123456789012), "T+8123.456789012s"},
+		{Time(time.Second + 5), "T+1.000000005s"},
+		{Time(-1500 * time.Millisecond), "T-1.500000000s"},
+		{math.MaxInt64, "T+9223372036.854775807s"},
+		{math.MinInt64, "T-9223372036.854775808s"},
+	} {
+		if got := tt.t.String(); got != tt.want {
+			t.Errorf("Time(%d).String() = %q, want %q", int64(tt.t), got, tt.want)
+		}
+	}
+	if got, want := RawTime(2*time.Second).String(), "raw+2.000000000s"; got != want {
+		t.Errorf("RawTime.String() = %q, want %q", got, want)
+	}
+}
+
+func TestMinMaxClamp(t *testing.T) {
+	if got := Min(1, 2); got != 1 {
+		t.Errorf("Min(1, 2) = %d", got)
+	}
+	if got := Min(2, 1); got != 1 {
+		t.Errorf("Min(2, 1) = %d", got)
+	}
+	if got := Max(1, 2); got != 2 {
+		t.Errorf("Max(1, 2) = %d", got)
+	}
+	if got := Max(2, 1); got != 2 {
+		t.Errorf("Max(2, 1) = %d", got)
+	}
+	for _, tt := range []struct{ t, lo, hi, want Time }{
+		{5, 1, 10, 5},
+		{0, 1, 10, 1},
+		{11, 1, 10, 10},
+		{5, 10, 1, 10},
+	} {
+		if got := tt.t.Clamp(tt.lo, tt.hi); got != tt.want {
+			t.Errorf("%d.Clamp(%d, %d) = %d, want %d", tt.t, tt.lo, tt.hi, got, tt.want)
+		}
+	}
+}
+
+func TestTimeIsZero(t *testing.T) {
+	var unset Time
+	if !unset.IsZero() || unset.IsSet() {
+		t.Error("the zero Time is set")
+	}
+	if now := Now(); now.IsZero() || !now.IsSet() {
+		t.Errorf("Now() = %d, not set", now)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	early, late := Time(1), Time(2)
+	for _, tt := range []struct {
+		t, u                 Time
+		before, after, equal bool
+		compare              int
+	}{
+		{early, late, true, false, false, -1},
+		{late, early, false, true, false, +1},
+		{late, late, false, false, true, 0},
+	} {
+		if got := tt.t.Before(tt.u); got != tt.before {
+			t.Errorf("%d.Before(%d) = %v", tt.t, tt.u, got)
+		}
+		if got := tt.t.After(tt.u); got != tt.after {
+			t.Errorf("%d.After(%d) = %v", tt.t, tt.u, got)
+		}
+		if got := tt.t.Equal(tt.u); got != tt.equal {
+			t.Errorf("%d.Equal(%d) = %v", tt.t, tt.u, got)
+		}
+		if got := tt.t.Compare(tt.u); got != tt.compare {
+			t.Errorf("%d.Compare(%d) = %d, want %d", tt.t, tt.u, got, tt.compare)
+		}
+	}
+}
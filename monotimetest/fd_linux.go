@@ -0,0 +1,28 @@
+package monotimetest
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// timerFDs returns the process's timerfd and io_uring descriptors, keyed by
+// number, with what they link to.
+func timerFDs() map[string]string {
+	const dir = "/proc/self/fd"
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	found := make(map[string]string)
+	for _, e := range entries {
+		link, err := os.Readlink(filepath.Join(dir, e.Name()))
+		if err != nil {
+			continue
+		}
+		if link == "anon_inode:[timerfd]" || link == "anon_inode:[io_uring]" {
+			found[e.Name()] = e.Name() + " -> " + link
+		}
+	}
+	return found
+}
@@ -0,0 +1,9 @@
+//go:build !linux
+// +build !linux
+
+package monotimetest
+
+// timerFDs returns nothing; descriptors are only tracked on Linux.
+func timerFDs() map[string]string {
+	return nil
+}
@@ -0,0 +1,127 @@
+// Package monotimetest provides helpers for testing code that uses monotime.
+package monotimetest
+
+import (
+	"bytes"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// leakGrace is how long VerifyNoLeaks waits for stopped timers' goroutines to
+// exit and their descriptors to close before reporting them.
+const leakGrace = 2 * time.Second
+
+// pkgPath begins the names of the functions of monotime and its subpackages
+// in goroutine traces, followed by "." or by "/" and the rest of the path.
+const pkgPath = "github.com/thisguycodes/monotime"
+
+// longLived are the goroutines monotime starts on purpose and keeps, which
+// are not leaks: the shared poller's workers, and checked mode's watch for
+// receives from stopped tickers, which ends on its own.
+var longLived = []string{
+	pkgPath + ".(*sharedPoller).wait",
+	pkgPath + ".watchStaleReads",
+}
+
+// VerifyNoLeaks fails t if, at the end of the test, any kernel timer or timer
+// goroutine that monotime created during it is still around: a Ticker or
+// Timer that was never stopped, say. It is like goleak, but knows which
+// goroutines and descriptors are monotime's. Timers are given a moment to
+// wind down after the test returns. Descriptors are only tracked on Linux,
+// where they are timerfds and io_uring instances.
+func VerifyNoLeaks(t testing.TB) {
+	t.Helper()
+	goroutines, fds := timerGoroutines(), timerFDs()
+	t.Cleanup(func() {
+		deadline := time.Now().Add(leakGrace)
+		for {
+			g, f := subtract(timerGoroutines(), goroutines), subtract(timerFDs(), fds)
+			if len(g) == 0 && len(f) == 0 {
+				return
+			}
+			if time.Now().After(deadline) {
+				for _, stack := range g {
+					t.Errorf("monotime goroutine leaked:\n%s", stack)
+				}
+				for _, fd := range f {
+					t.Errorf("monotime descriptor leaked: %s", fd)
+				}
+				return
+			}
+			time.Sleep(time.Millisecond)
+		}
+	})
+}
+
+// timerGoroutines returns the stacks of the running goroutines monotime
+// started, keyed by goroutine header, e.g. "goroutine 7 [select]".
+func timerGoroutines() map[string]string {
+	buf := make([]byte, 1<<20)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			buf = buf[:n]
+			break
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+
+	found := make(map[string]string)
+	for _, stack := range bytes.Split(buf, []byte("\n\n")) {
+		s := string(stack)
+		header := strings.SplitN(s, "\n", 2)[0]
+		// The goroutine's ID; its state changes as it runs.
+		id := strings.SplitN(header, " [", 2)[0]
+		if startedByMonotime(s) {
+			found[id] = s
+		}
+	}
+	return found
+}
+
+// startedByMonotime reports whether the goroutine whose trace is stack was
+// started by monotime, other than by a test, and is not one it keeps.
+func startedByMonotime(stack string) bool {
+	i := strings.LastIndex(stack, "\ncreated by ")
+	if i < 0 {
+		return false
+	}
+	name, ok := monotimeFunc(stack[i+len("\ncreated by "):])
+	if !ok || strings.HasPrefix(name, "Test") || strings.HasPrefix(name, "Benchmark") {
+		return false
+	}
+	for _, fn := range longLived {
+		if strings.Contains(stack, "\n"+fn+"(") {
+			return false
+		}
+	}
+	return true
+}
+
+// monotimeFunc returns the name of fn within its package, if fn is a function
+// of monotime or one of its subpackages.
+func monotimeFunc(fn string) (string, bool) {
+	rest := strings.TrimPrefix(fn, pkgPath)
+	if len(rest) == len(fn) || rest == "" || (rest[0] != '.' && rest[0] != '/') {
+		return "", false
+	}
+	// The package path runs up to the first dot after it.
+	i := strings.IndexByte(rest, '.')
+	if i < 0 {
+		return "", false
+	}
+	return rest[i+1:], true
+}
+
+// subtract returns the values of now whose keys are not in before.
+func subtract(now, before map[string]string) []string {
+	var left []string
+	for k, v := range now {
+		if _, ok := before[k]; !ok {
+			left = append(left, v)
+		}
+	}
+	return left
+}
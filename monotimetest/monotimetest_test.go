@@ -0,0 +1,92 @@
+package monotimetest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/thisguycodes/monotime"
+	"github.com/thisguycodes/monotime/compat"
+)
+
+// recorder is a testing.TB that records failures and runs cleanups when
+// asked, so VerifyNoLeaks can be seen to fail.
+type recorder struct {
+	testing.TB
+	errors   []string
+	cleanups []func()
+}
+
+func (r *recorder) Helper()          {}
+func (r *recorder) Cleanup(f func()) { r.cleanups = append(r.cleanups, f) }
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func (r *recorder) finish() {
+	for i := len(r.cleanups) - 1; i >= 0; i-- {
+		r.cleanups[i]()
+	}
+}
+
+func TestVerifyNoLeaks(t *testing.T) {
+	r := &recorder{TB: t}
+	VerifyNoLeaks(r)
+	ticker := monotime.NewTicker(time.Millisecond)
+	<-ticker.C
+	ticker.Stop()
+	timer := monotime.NewTimer(time.Hour)
+	timer.Stop()
+	r.finish()
+	if len(r.errors) > 0 {
+		t.Errorf("leaks reported for stopped timers:\n%s", strings.Join(r.errors, "\n"))
+	}
+}
+
+func TestVerifyNoLeaksReports(t *testing.T) {
+	r := &recorder{TB: t}
+	VerifyNoLeaks(r)
+	ticker := monotime.NewTicker(time.Hour)
+	r.finish()
+	ticker.Stop()
+	if len(r.errors) == 0 {
+		t.Error("no leak reported for a ticker never stopped")
+	}
+	for _, e := range r.errors {
+		if !strings.Contains(e, "monotime") {
+			t.Errorf("leak report %q doesn't mention monotime", e)
+		}
+	}
+}
+
+func TestVerifyNoLeaksSubpackage(t *testing.T) {
+	r := &recorder{TB: t}
+	VerifyNoLeaks(r)
+	timer := compat.NewTimer(time.Hour)
+	r.finish()
+	timer.Stop()
+	if len(r.errors) == 0 {
+		t.Error("no leak reported for a compat timer never stopped")
+	}
+}
+
+func TestMonotimeFunc(t *testing.T) {
+	for _, tt := range []struct {
+		fn   string
+		name string
+		ok   bool
+	}{
+		{"github.com/thisguycodes/monotime.NewTicker", "NewTicker", true},
+		{"github.com/thisguycodes/monotime.(*wheel).run", "(*wheel).run", true},
+		{"github.com/thisguycodes/monotime/compat.(*Timer).start", "(*Timer).start", true},
+		{"github.com/thisguycodes/monotime/compat.TestTimer", "TestTimer", true},
+		{"github.com/thisguycodes/monotimeother.Run", "", false},
+		{"github.com/thisguycodes/monotime", "", false},
+		{"main.main", "", false},
+	} {
+		if name, ok := monotimeFunc(tt.fn); name != tt.name || ok != tt.ok {
+			t.Errorf("monotimeFunc(%q) = %q, %v; want %q, %v", tt.fn, name, ok, tt.name, tt.ok)
+		}
+	}
+}
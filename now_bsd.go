@@ -0,0 +1,22 @@
+//go:build dragonfly || freebsd || netbsd || openbsd
+// +build dragonfly freebsd netbsd openbsd
+
+package monotime
+
+import (
+	"fmt"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// nowErr reads CLOCK_MONOTONIC. x/sys/unix has no clock_gettime wrapper for
+// the BSDs, so the system call is made directly.
+func nowErr() (Time, error) {
+	var spec unix.Timespec
+	_, _, errno := unix.Syscall(unix.SYS_CLOCK_GETTIME, clockMonotonic, uintptr(unsafe.Pointer(&spec)), 0)
+	if errno != 0 {
+		return 0, fmt.Errorf("Error getting monotime from the kernel: %w", errno)
+	}
+	return Time(spec.Nano()), nil
+}
@@ -0,0 +1,20 @@
+package monotime
+
+import (
+	"fmt"
+
+	"golang.org/x/sys/unix"
+)
+
+// nowErr reads CLOCK_UPTIME_RAW, which is mach_absolute_time in nanoseconds.
+// Like Linux's CLOCK_MONOTONIC it stops while the system sleeps; Darwin's
+// own CLOCK_MONOTONIC does not. NowBoottime reads the clock that keeps
+// counting, in its unadjusted form, CLOCK_MONOTONIC_RAW.
+func nowErr() (Time, error) {
+	spec := new(unix.Timespec)
+	err := unix.ClockGettime(unix.CLOCK_UPTIME_RAW, spec)
+	if err != nil {
+		return 0, fmt.Errorf("Error getting monotime from the kernel: %w", err)
+	}
+	return Time(spec.Nano()), nil
+}
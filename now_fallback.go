@@ -0,0 +1,16 @@
+//go:build !darwin && !dragonfly && !freebsd && !js && !linux && !netbsd && !openbsd && !windows
+// +build !darwin,!dragonfly,!freebsd,!js,!linux,!netbsd,!openbsd,!windows
+
+package monotime
+
+import "time"
+
+// epoch anchors Now on platforms without a native backend. It is set a second
+// before the package is initialized, so that no reading is the zero Time.
+var epoch = time.Now().Add(-time.Second)
+
+// nowErr reads the runtime's own monotonic clock, through the reading
+// time.Now carries, as the nanoseconds since epoch. It cannot fail.
+func nowErr() (Time, error) {
+	return Time(time.Since(epoch)), nil
+}
@@ -0,0 +1,22 @@
+package monotime
+
+import (
+	"math"
+	"syscall/js"
+	"time"
+)
+
+// performance is the JavaScript High Resolution Time API, present in browsers
+// and Node.js.
+var performance = js.Global().Get("performance")
+
+// nowOffset is added to every reading of performance.now(), which starts at 0,
+// so that no reading is the zero Time.
+const nowOffset = Time(time.Second)
+
+// nowErr reads performance.now(), the milliseconds since the page or process
+// started, scaled to nanoseconds and offset by nowOffset. Browsers coarsen it,
+// to as much as 100µs, against timing attacks. It cannot fail.
+func nowErr() (Time, error) {
+	return Time(math.Round(performance.Call("now").Float()*1e6)) + nowOffset, nil
+}
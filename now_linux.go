@@ -0,0 +1,17 @@
+package monotime
+
+import (
+	"fmt"
+
+	"golang.org/x/sys/unix"
+)
+
+// nowErr reads CLOCK_MONOTONIC, which stops while the system is suspended.
+func nowErr() (Time, error) {
+	spec := new(unix.Timespec)
+	err := unix.ClockGettime(unix.CLOCK_MONOTONIC, spec)
+	if err != nil {
+		return 0, fmt.Errorf("Error getting monotime from the kernel: %w", err)
+	}
+	return Time(spec.Nano()), nil
+}
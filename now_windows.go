@@ -0,0 +1,63 @@
+package monotime
+
+import (
+	"fmt"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	procQueryPerformanceCounter   = kernel32.NewProc("QueryPerformanceCounter")
+	procQueryPerformanceFrequency = kernel32.NewProc("QueryPerformanceFrequency")
+
+	realtimeAPI                           = syscall.NewLazyDLL("api-ms-win-core-realtime-l1-1-1.dll")
+	procQueryUnbiasedInterruptTimePrecise = realtimeAPI.NewProc("QueryUnbiasedInterruptTimePrecise")
+)
+
+// qpcFrequency is the rate of the performance counter in counts per second.
+// It is fixed at boot.
+var qpcFrequency = func() int64 {
+	var f int64
+	if r, _, err := procQueryPerformanceFrequency.Call(uintptr(unsafe.Pointer(&f))); r == 0 {
+		err = fmt.Errorf("Error getting performance counter frequency from the kernel: %w", err)
+		panic(err)
+	}
+	return f
+}()
+
+// hasUnbiasedTime reports whether QueryUnbiasedInterruptTimePrecise is
+// available, as it is from Windows 10.
+var hasUnbiasedTime = procQueryUnbiasedInterruptTimePrecise.Find() == nil
+
+// nowErr reads QueryUnbiasedInterruptTimePrecise, which has the precision of the
+// performance counter but, like Linux's CLOCK_MONOTONIC, stops while the
+// system sleeps or hibernates. Before Windows 10 it falls back on
+// QueryPerformanceCounter itself, which keeps counting through sleep.
+func nowErr() (Time, error) {
+	if hasUnbiasedTime {
+		// The interrupt time is in 100ns units; the call can't fail.
+		var t uint64
+		procQueryUnbiasedInterruptTimePrecise.Call(uintptr(unsafe.Pointer(&t)))
+		return Time(t * 100), nil
+	}
+	return readQPCErr()
+}
+
+// readQPC reads QueryPerformanceCounter, scaled to nanoseconds.
+func readQPC() Time {
+	t, err := readQPCErr()
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// readQPCErr is like readQPC, but returns an error instead of panicking.
+func readQPCErr() (Time, error) {
+	var c int64
+	if r, _, err := procQueryPerformanceCounter.Call(uintptr(unsafe.Pointer(&c))); r == 0 {
+		return 0, fmt.Errorf("Error getting monotime from the kernel: %w", err)
+	}
+	// Split the scaling so c*1e9 can't overflow.
+	return Time(c/qpcFrequency*1e9 + c%qpcFrequency*1e9/qpcFrequency), nil
+}
@@ -0,0 +1,126 @@
+package monotime
+
+import "time"
+
+// TickerOption configures a Ticker created by NewTicker.
+type TickerOption func(*tickerConfig)
+
+// tickerConfig is what a Ticker's options set.
+type tickerConfig struct {
+	clock        Clock
+	start        Time
+	mode         tickerMode
+	policy       MissedTickPolicy
+	maxCatchUp   uint64
+	lagThreshold time.Duration
+	buffer       int
+	slack        time.Duration
+
+	// newTimer creates the kernel timer, if not the clock's usual kind.
+	newTimer func(c Clock) (*kernelTimer, error)
+
+	// launch starts the kernel timer's goroutine, if not with its start
+	// method.
+	launch func(kt *kernelTimer, fire func(n uint64) bool) error
+}
+
+// tickerMode is how a Ticker schedules its ticks.
+type tickerMode int
+
+const (
+	periodic   tickerMode = iota // the kernel timer's own interval
+	fixedRate                    // a one-shot timer, armed at each due time
+	fixedDelay                   // a one-shot timer, armed on each Ack
+)
+
+// WithClock runs the ticker on clock c instead of the monotonic clock. Only
+// ClockMonotonic, ClockBoottime and ClockBoottimeAlarm have timers, and not on
+// every host; for the others NewTicker fails.
+//
+// A ticker on ClockBoottime keeps its schedule across system suspend: a
+// ticker every minute that sleeps through an hour delivers the ticks it
+// missed on resume, as WithMaxCatchUp allows, instead of picking up where it
+// left off. One on ClockBoottimeAlarm also wakes the system from suspend when
+// a tick comes due.
+func WithClock(c Clock) TickerOption {
+	return func(cfg *tickerConfig) {
+		cfg.clock = c
+	}
+}
+
+// WithStartAt makes the ticker's first tick due at t, a time read from the
+// ticker's clock, instead of d from now. A start in the past makes the ticks
+// since then due at once. The zero Time leaves the start d from now.
+func WithStartAt(t Time) TickerOption {
+	return func(cfg *tickerConfig) {
+		cfg.start = t
+	}
+}
+
+// WithMissedTickPolicy sets how the ticker delivers ticks that came due while
+// its receiver was busy. The default is DeliverAll. Ticks that Coalesce and
+// Resync drop are counted by Skipped.
+func WithMissedTickPolicy(p MissedTickPolicy) TickerOption {
+	if p < DeliverAll || p > Resync {
+		panic("unknown policy for WithMissedTickPolicy")
+	}
+	return func(cfg *tickerConfig) {
+		cfg.policy = p
+	}
+}
+
+// WithMaxCatchUp limits how many ticks the ticker delivers back to back for
+// expirations that pile up while it can't deliver, as when the receiver
+// falls behind or the process is paused. Past the limit, missed ticks are
+// dropped and counted by Skipped. A limit of zero, the default, delivers
+// every tick.
+func WithMaxCatchUp(n int) TickerOption {
+	if n < 0 {
+		panic("negative limit for WithMaxCatchUp")
+	}
+	return func(cfg *tickerConfig) {
+		cfg.maxCatchUp = uint64(n)
+	}
+}
+
+// WithLagThreshold makes the ticker report on Lagging whenever a tick is
+// received more than threshold after it came due, so a service can shed load
+// or alert rather than quietly work through stale ticks. A threshold of zero,
+// the default, turns the reports off.
+func WithLagThreshold(threshold time.Duration) TickerOption {
+	if threshold < 0 {
+		panic("negative threshold for WithLagThreshold")
+	}
+	return func(cfg *tickerConfig) {
+		cfg.lagThreshold = threshold
+	}
+}
+
+// WithBuffer gives the ticker's channel room for n ticks, so a receiver busy
+// for up to n periods doesn't hold up the ticker's goroutine, which can then
+// go on reading the kernel timer and notice promptly if it fails. Lag is
+// measured when a tick enters the buffer rather than when it is received. The
+// default, zero, is an unbuffered channel.
+func WithBuffer(n int) TickerOption {
+	if n < 0 {
+		panic("negative size for WithBuffer")
+	}
+	return func(cfg *tickerConfig) {
+		cfg.buffer = n
+	}
+}
+
+// WithSlack lets each of the ticker's ticks fire up to s late: the kernel
+// timer is armed for the due time rounded up to a multiple of s, so tickers
+// with the same slack wake the system together rather than each on its own.
+// Ticks still carry their due times. The kernel adds no slack of its own to
+// the timers tickers use, so the default, zero, fires each tick as close to
+// its due time as the kernel allows.
+func WithSlack(s time.Duration) TickerOption {
+	if s < 0 {
+		panic("negative slack for WithSlack")
+	}
+	return func(cfg *tickerConfig) {
+		cfg.slack = s
+	}
+}
@@ -0,0 +1,50 @@
+package monotime
+
+import "time"
+
+// Pacer schedules events at a fixed rate on the monotonic clock.
+//
+// The schedule is open-loop: the n-th event is due at start+n*interval no
+// matter how long earlier events took to handle, so a slow consumer falls
+// behind the schedule rather than silently stretching it. A Pacer is not safe
+// for concurrent use.
+type Pacer struct {
+	start    Time
+	interval time.Duration
+	n        int64
+}
+
+// NewPacer returns a Pacer whose first event is due immediately.
+func NewPacer(interval time.Duration) *Pacer {
+	return NewPacerAt(Now(), interval)
+}
+
+// NewPacerAt returns a Pacer whose first event is due at start.
+func NewPacerAt(start Time, interval time.Duration) *Pacer {
+	if interval <= 0 {
+		panic("non-positive interval for NewPacer")
+	}
+	return &Pacer{start: start, interval: interval}
+}
+
+// Interval returns the time between scheduled events.
+func (p *Pacer) Interval() time.Duration {
+	return p.interval
+}
+
+// Next returns the time the next event is due and advances the schedule,
+// without waiting.
+func (p *Pacer) Next() Time {
+	t := p.start.AddSat(mulSat(p.n, p.interval))
+	p.n++
+	return t
+}
+
+// Wait sleeps until the next event is due, advances the schedule, and returns
+// the time the event was due. Comparing the result with Now measures how late
+// the caller is being woken.
+func (p *Pacer) Wait() Time {
+	t := p.Next()
+	SleepUntil(t)
+	return t
+}
@@ -0,0 +1,38 @@
+package monotime
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestPacer(t *testing.T) {
+	p := NewPacerAt(100, time.Second)
+	for i := 0; i < 3; i++ {
+		if got, want := p.Next(), Time(100).Add(time.Duration(i)*time.Second); got != want {
+			t.Errorf("event %d due at %v, want %v", i, got, want)
+		}
+	}
+	if p.Interval() != time.Second {
+		t.Errorf("Interval() = %v", p.Interval())
+	}
+
+	p = NewPacer(time.Millisecond)
+	p.Wait()
+	due := p.Wait()
+	if Now() < due {
+		t.Error("Wait returned before the event was due")
+	}
+}
+
+func TestPacerSaturates(t *testing.T) {
+	p := NewPacerAt(math.MaxInt64-10, time.Second)
+	if got := p.Next(); got != math.MaxInt64-10 {
+		t.Errorf("first event due at %d", got)
+	}
+	for i := 1; i < 3; i++ {
+		if got := p.Next(); got != math.MaxInt64 {
+			t.Errorf("event %d due at %d, want the end of time", i, got)
+		}
+	}
+}
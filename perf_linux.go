@@ -0,0 +1,55 @@
+package monotime
+
+import (
+	"fmt"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// FromPerfTime converts the time of a perf sample, in nanoseconds, into a
+// Time. clockid is the clock the samples were recorded with: the clockid of
+// the perf_event_attr, or perf record's -k option. Samples on CLOCK_MONOTONIC
+// convert exactly; those on other clocks are converted at the clock's current
+// offset from CLOCK_MONOTONIC, which is exact only until the clocks next move
+// apart. perf's default clock, which clock_gettime can't read, is not
+// supported; correlate it with the tracecorr package instead.
+func FromPerfTime(ns uint64, clockid int32) (Time, error) {
+	off, err := perfClockOffset(clockid)
+	if err != nil {
+		return 0, err
+	}
+	return Time(int64(ns) - int64(off)), nil
+}
+
+// ToPerfTime converts a Time into a perf sample time in nanoseconds on clock
+// clockid, as FromPerfTime's inverse.
+func ToPerfTime(t Time, clockid int32) (uint64, error) {
+	off, err := perfClockOffset(clockid)
+	if err != nil {
+		return 0, err
+	}
+	return uint64(int64(t) + int64(off)), nil
+}
+
+// RawOffset returns how far CLOCK_MONOTONIC_RAW was ahead of CLOCK_MONOTONIC
+// when s was taken. NTP adjusts the rate of CLOCK_MONOTONIC and not the raw
+// clock's, so the offset drifts, by up to 500µs a second; to convert a long
+// trace recorded on CLOCK_MONOTONIC_RAW, take a sample before and after it
+// and interpolate between their offsets.
+func RawOffset(s ClockSample) time.Duration {
+	return time.Duration(int64(s.Raw) - int64(s.Monotonic))
+}
+
+// perfClockOffset returns how far clockid is ahead of CLOCK_MONOTONIC.
+func perfClockOffset(clockid int32) (time.Duration, error) {
+	switch clockid {
+	case unix.CLOCK_MONOTONIC:
+		return 0, nil
+	case unix.CLOCK_MONOTONIC_RAW:
+		return RawOffset(SampleClocks()), nil
+	case unix.CLOCK_BOOTTIME, unix.CLOCK_REALTIME, unix.CLOCK_TAI:
+		return clockOffset(clockid), nil
+	}
+	return 0, fmt.Errorf("Error converting perf timestamp: unsupported clock %d", clockid)
+}
@@ -0,0 +1,48 @@
+package monotime
+
+import (
+	"testing"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+func TestPerfTime(t *testing.T) {
+	for _, clockid := range []int32{unix.CLOCK_MONOTONIC, unix.CLOCK_MONOTONIC_RAW, unix.CLOCK_BOOTTIME, unix.CLOCK_REALTIME, unix.CLOCK_TAI} {
+		// A sample stamped now on the clock converts to the present.
+		now := Now()
+		got, err := FromPerfTime(uint64(readClock(clockid)), clockid)
+		if err != nil {
+			t.Fatalf("clock %d: %v", clockid, err)
+		}
+		if d := got.Sub(now); d < -time.Millisecond || d > time.Second {
+			t.Errorf("clock %d: sample converted to %v from now", clockid, d)
+		}
+
+		ns, err := ToPerfTime(now, clockid)
+		if err != nil {
+			t.Fatalf("clock %d: %v", clockid, err)
+		}
+		back, err := FromPerfTime(ns, clockid)
+		if err != nil {
+			t.Fatalf("clock %d: %v", clockid, err)
+		}
+		if d := back.Sub(now); d < -time.Millisecond || d > time.Millisecond {
+			t.Errorf("clock %d: round trip moved %v", clockid, d)
+		}
+	}
+	if _, err := FromPerfTime(0, unix.CLOCK_PROCESS_CPUTIME_ID); err == nil {
+		t.Error("FromPerfTime accepted a CPU-time clock")
+	}
+	if _, err := ToPerfTime(0, -1); err == nil {
+		t.Error("ToPerfTime accepted an invalid clock")
+	}
+}
+
+func TestRawOffset(t *testing.T) {
+	s := SampleClocks()
+	off := RawOffset(s)
+	if got := s.Monotonic.Add(off); RawTime(got) != s.Raw {
+		t.Errorf("Monotonic + RawOffset = %d, want %d", got, s.Raw)
+	}
+}
@@ -0,0 +1,105 @@
+package monotime
+
+import (
+	"os"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// PHC is an open PTP hardware clock, such as /dev/ptp0, read through the
+// kernel's dynamic clock IDs.
+type PHC struct {
+	f       *os.File
+	clockid int32
+}
+
+// PHCOffset is a measurement of a PTP hardware clock against
+// CLOCK_MONOTONIC.
+type PHCOffset struct {
+	// Offset is PHC time minus monotonic time, in nanoseconds.
+	Offset time.Duration
+	// Mono is the monotonic time the measurement was taken at.
+	Mono Time
+	// Uncertainty bounds the error of Offset: the time the tightest
+	// bracketed read of the PHC took.
+	Uncertainty time.Duration
+}
+
+// ToMono converts a reading of the clock, as from Read, into the monotonic time
+// it was taken at, according to the offset o. The conversion drifts as the two
+// clocks do, so o should be measured near the reading.
+func (o PHCOffset) ToMono(phc int64) Time {
+	return Time(phc - int64(o.Offset))
+}
+
+// ToPHC converts a monotonic time into a reading of the clock, as ToMono's
+// inverse.
+func (o PHCOffset) ToPHC(t Time) int64 {
+	return int64(t) + int64(o.Offset)
+}
+
+// phcAttempts is how many bracketed reads PHC.Offset takes before settling on
+// the tightest one.
+const phcAttempts = 9
+
+// OpenPHC opens the PTP hardware clock device at path.
+func OpenPHC(path string) (*PHC, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	p := &PHC{f: f, clockid: fdToClockID(f.Fd())}
+	var ts unix.Timespec
+	if err := unix.ClockGettime(p.clockid, &ts); err != nil {
+		f.Close()
+		return nil, &os.PathError{Op: "clock_gettime", Path: path, Err: err}
+	}
+	return p, nil
+}
+
+// fdToClockID is the kernel's FD_TO_CLOCKID: the dynamic clock ID that reads
+// the clock device open on fd.
+func fdToClockID(fd uintptr) int32 {
+	return int32((^int(fd) << 3) | 3)
+}
+
+// Close closes the clock device.
+func (p *PHC) Close() error {
+	return p.f.Close()
+}
+
+// Read returns the current time of the clock in nanoseconds since its epoch,
+// usually TAI when it is disciplined by PTP.
+func (p *PHC) Read() (int64, error) {
+	var ts unix.Timespec
+	if err := unix.ClockGettime(p.clockid, &ts); err != nil {
+		return 0, err
+	}
+	return ts.Nano(), nil
+}
+
+// Offset measures the clock against CLOCK_MONOTONIC by bracketing reads of it
+// between monotonic reads, keeping the tightest of several attempts.
+func (p *PHC) Offset() (PHCOffset, error) {
+	var best PHCOffset
+	for i := 0; i < phcAttempts; i++ {
+		before := Now()
+		phc, err := p.Read()
+		after := Now()
+		if err != nil {
+			return PHCOffset{}, err
+		}
+
+		spread := after.Sub(before)
+		if i == 0 || spread < best.Uncertainty {
+			mid := before.Add(spread / 2)
+			best = PHCOffset{
+				Offset:      time.Duration(phc - int64(mid)),
+				Mono:        mid,
+				Uncertainty: spread,
+			}
+		}
+	}
+	return best, nil
+}
@@ -0,0 +1,79 @@
+package monotime
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+func TestPHCOffsetConversions(t *testing.T) {
+	o := PHCOffset{Offset: 1500 * time.Second}
+	mono := Time(42 * time.Second)
+	phc := o.ToPHC(mono)
+	if want := int64(1542 * time.Second); phc != want {
+		t.Errorf("ToPHC(%d) = %d, want %d", mono, phc, want)
+	}
+	if got := o.ToMono(phc); got != mono {
+		t.Errorf("ToMono(%d) = %d, want %d", phc, got, mono)
+	}
+}
+
+func TestOpenPHC(t *testing.T) {
+	p, err := OpenPHC("/dev/ptp0")
+	if errors.Is(err, os.ErrNotExist) {
+		t.Skip("no PTP hardware clock")
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p.Close()
+
+	o, err := p.Offset()
+	if err != nil {
+		t.Fatal(err)
+	}
+	phc, err := p.Read()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := Now().Sub(o.ToMono(phc)); d < -time.Millisecond || d > time.Second {
+		t.Errorf("PHC reading converts to %v from now", d)
+	}
+}
+
+func TestPHCOffset(t *testing.T) {
+	// Measured against CLOCK_MONOTONIC itself, as a stand-in for hardware,
+	// the offset is zero to within its uncertainty.
+	p := &PHC{clockid: unix.CLOCK_MONOTONIC}
+	o, err := p.Offset()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if o.Uncertainty < 0 || o.Uncertainty > time.Millisecond {
+		t.Errorf("uncertainty %v", o.Uncertainty)
+	}
+	if o.Offset < -o.Uncertainty || o.Offset > o.Uncertainty {
+		t.Errorf("offset of CLOCK_MONOTONIC from itself %v, beyond the uncertainty %v", o.Offset, o.Uncertainty)
+	}
+	if d := Since(o.Mono); d < 0 || d > time.Second {
+		t.Errorf("measurement taken %v ago", d)
+	}
+}
+
+func TestOpenPHCNotAClock(t *testing.T) {
+	_, err := OpenPHC("/dev/null")
+	var pathErr *os.PathError
+	if !errors.As(err, &pathErr) || pathErr.Op != "clock_gettime" {
+		t.Errorf("OpenPHC of a device that isn't a clock = %v, want a clock_gettime error", err)
+	}
+}
+
+func TestFDToClockID(t *testing.T) {
+	// FD_TO_CLOCKID(3), as the kernel's posix-timers.h computes it.
+	if got, want := fdToClockID(3), int32(-29); got != want {
+		t.Errorf("fdToClockID(3) = %d, want %d", got, want)
+	}
+}
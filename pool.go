@@ -0,0 +1,106 @@
+package monotime
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrDeadlineExceeded is returned for a task whose deadline passed before it
+// could start.
+var ErrDeadlineExceeded = errors.New("task deadline exceeded before it started")
+
+// ErrPoolClosed is returned by Submit after the pool has been closed.
+var ErrPoolClosed = errors.New("worker pool is closed")
+
+// Task is a unit of work for a Pool. The context it receives expires at the
+// task's deadline.
+type Task func(ctx context.Context) error
+
+// Pool runs tasks on a fixed number of workers. Every task carries a monotonic
+// deadline: a task still queued when its deadline passes is rejected without
+// running, and a running task's context is cancelled exactly at it.
+type Pool struct {
+	tasks chan poolTask
+	wg    sync.WaitGroup
+
+	mu     sync.RWMutex
+	closed bool
+}
+
+type poolTask struct {
+	run      Task
+	deadline Time
+	result   chan error
+}
+
+// NewPool starts a Pool with the given number of workers and room to queue
+// as many tasks again before Submit blocks. workers must be greater than
+// zero; if not, NewPool will panic.
+func NewPool(workers int) *Pool {
+	if workers <= 0 {
+		panic("non-positive worker count for NewPool")
+	}
+	p := &Pool{tasks: make(chan poolTask, workers)}
+	p.wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go p.work()
+	}
+	return p
+}
+
+// Submit queues task to run before deadline and returns a channel that
+// receives its result: the task's error, or ErrDeadlineExceeded if it was
+// rejected. Submit itself returns ErrDeadlineExceeded without queueing if the
+// deadline has already passed, and ErrPoolClosed after Close.
+func (p *Pool) Submit(deadline Time, task Task) (<-chan error, error) {
+	if !Now().Before(deadline) {
+		return nil, ErrDeadlineExceeded
+	}
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.closed {
+		return nil, ErrPoolClosed
+	}
+	result := make(chan error, 1)
+	p.tasks <- poolTask{run: task, deadline: deadline, result: result}
+	return result, nil
+}
+
+// Do submits task with a deadline of b and waits for its result.
+func (p *Pool) Do(b Budget, task Task) error {
+	result, err := p.Submit(b.Deadline(), task)
+	if err != nil {
+		return err
+	}
+	return <-result
+}
+
+// Close stops accepting tasks, waits for the queued ones to run or be
+// rejected, and stops the workers.
+func (p *Pool) Close() {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return
+	}
+	p.closed = true
+	close(p.tasks)
+	p.mu.Unlock()
+	p.wg.Wait()
+}
+
+func (p *Pool) work() {
+	defer p.wg.Done()
+	for t := range p.tasks {
+		b := BudgetUntil(t.deadline)
+		if b.Exhausted() {
+			t.result <- ErrDeadlineExceeded
+			continue
+		}
+		ctx, cancel := b.Context(context.Background())
+		t.result <- t.run(ctx)
+		cancel()
+	}
+}
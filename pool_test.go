@@ -0,0 +1,92 @@
+package monotime
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestPool(t *testing.T) {
+	p := NewPool(2)
+	defer p.Close()
+	errTask := errors.New("task failed")
+	if err := p.Do(NewBudget(time.Second), func(context.Context) error { return errTask }); err != errTask {
+		t.Errorf("Do() = %v, want the task's error", err)
+	}
+}
+
+func TestPoolCancelsAtDeadline(t *testing.T) {
+	p := NewPool(1)
+	defer p.Close()
+	deadline := Now().Add(5 * time.Millisecond)
+	result, err := p.Submit(deadline, func(ctx context.Context) error {
+		<-ctx.Done()
+		if Now().Before(deadline) {
+			return errors.New("context cancelled before the deadline")
+		}
+		return ctx.Err()
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case err := <-result:
+		if err != context.DeadlineExceeded {
+			t.Errorf("task returned %v, want context.DeadlineExceeded", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("task's context was not cancelled")
+	}
+}
+
+func TestPoolRejectsExpired(t *testing.T) {
+	p := NewPool(1)
+	defer p.Close()
+	if _, err := p.Submit(Now(), func(context.Context) error { return nil }); err != ErrDeadlineExceeded {
+		t.Errorf("Submit past its deadline = %v, want ErrDeadlineExceeded", err)
+	}
+
+	// A task whose deadline passes while it waits behind another is
+	// rejected without running.
+	release := make(chan struct{})
+	busy, err := p.Submit(Now().Add(time.Hour), func(context.Context) error {
+		<-release
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ran := false
+	queued, err := p.Submit(Now().Add(5*time.Millisecond), func(context.Context) error {
+		ran = true
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	<-busy
+	if err := <-queued; err != ErrDeadlineExceeded || ran {
+		t.Errorf("queued task past its deadline returned %v, ran %v; want ErrDeadlineExceeded without running", err, ran)
+	}
+}
+
+func TestPoolClose(t *testing.T) {
+	p := NewPool(1)
+	p.Close()
+	p.Close()
+	if _, err := p.Submit(Now().Add(time.Hour), func(context.Context) error { return nil }); err != ErrPoolClosed {
+		t.Errorf("Submit after Close = %v, want ErrPoolClosed", err)
+	}
+}
+
+func TestNewPoolPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("NewPool(0) did not panic")
+		}
+	}()
+	NewPool(0)
+}
@@ -0,0 +1,70 @@
+package monotime
+
+import (
+	"sync"
+	"time"
+)
+
+// preciseMargin is how far ahead of its deadline a PreciseTimer stops waiting
+// on a kernel timer and a channel, and starts sleeping in the waiter's own
+// goroutine: comfortably more than a wakeup and a channel hop take.
+const preciseMargin = time.Millisecond
+
+// PreciseTimer is a one-shot timer for waits where delivery latency matters.
+// A Timer's expiration reaches the waiter through the timer's goroutine and a
+// channel, and each hop adds scheduling delay. A PreciseTimer instead puts the
+// waiting goroutine itself to sleep with clock_nanosleep for the last stretch
+// before the deadline, so it wakes straight from the kernel, often tens of
+// microseconds sooner.
+//
+// The price is that Wait blocks the goroutine rather than offering a channel
+// to select on.
+type PreciseTimer struct {
+	deadline Time
+
+	stopOnce sync.Once
+	stop     chan struct{}
+}
+
+// NewPreciseTimer returns a PreciseTimer that expires when the monotonic clock
+// reaches t.
+func NewPreciseTimer(t Time) *PreciseTimer {
+	return &PreciseTimer{deadline: t, stop: make(chan struct{})}
+}
+
+// Wait blocks until the timer expires and reports true, or until it is
+// stopped and reports false. A Stop in the final millisecond before the
+// deadline is only noticed at the deadline. Any number of goroutines may
+// Wait.
+func (t *PreciseTimer) Wait() bool {
+	if early := t.deadline.Add(-preciseMargin); early.After(Now()) {
+		timer := NewTimerAtPrecision(early, Exact)
+		select {
+		case <-timer.C:
+		case <-t.stop:
+		}
+		timer.Stop()
+	}
+	if t.stopped() {
+		return false
+	}
+	SleepUntil(t.deadline)
+	return !t.stopped()
+}
+
+// Stop stops the timer, making any current or later Wait return false.
+// Calling Stop more than once has no further effect.
+func (t *PreciseTimer) Stop() {
+	t.stopOnce.Do(func() {
+		close(t.stop)
+	})
+}
+
+func (t *PreciseTimer) stopped() bool {
+	select {
+	case <-t.stop:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,43 @@
+package monotime
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPreciseTimer(t *testing.T) {
+	for _, d := range []time.Duration{0, preciseMargin / 2, 5 * time.Millisecond} {
+		deadline := Now().Add(d)
+		timer := NewPreciseTimer(deadline)
+		if !timer.Wait() {
+			t.Errorf("Wait for %v reported a stop", d)
+		}
+		if late := Since(deadline); late < 0 || late > 50*time.Millisecond {
+			t.Errorf("Wait for %v returned %v after the deadline", d, late)
+		}
+	}
+}
+
+func TestPreciseTimerStop(t *testing.T) {
+	timer := NewPreciseTimer(Now().Add(time.Hour))
+	result := make(chan bool)
+	for i := 0; i < 2; i++ {
+		go func() { result <- timer.Wait() }()
+	}
+	time.Sleep(5 * time.Millisecond)
+	timer.Stop()
+	timer.Stop()
+	for i := 0; i < 2; i++ {
+		select {
+		case ok := <-result:
+			if ok {
+				t.Error("Wait reported expiry after Stop")
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("Wait did not return after Stop")
+		}
+	}
+	if timer.Wait() {
+		t.Error("Wait after Stop reported expiry")
+	}
+}
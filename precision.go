@@ -0,0 +1,76 @@
+package monotime
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Precision is how punctually a timer must fire. Coarser classes let the
+// deadline slide later onto a shared grid, so that timers created at
+// different moments expire together and the system wakes up far less often.
+type Precision int32
+
+const (
+	// Exact timers fire as close to their deadline as the kernel allows.
+	Exact Precision = iota
+	// Coarse timers may fire up to 4ms late, on a 4ms grid. This suits
+	// most timeouts.
+	Coarse
+	// Lazy timers may fire up to 250ms late, on a 250ms grid. This suits
+	// housekeeping such as cache expiry and idle-connection reaping.
+	Lazy
+)
+
+func (p Precision) String() string {
+	switch p {
+	case Exact:
+		return "exact"
+	case Coarse:
+		return "coarse"
+	case Lazy:
+		return "lazy"
+	}
+	return "unknown"
+}
+
+// granularity returns the grid deadlines of class p are rounded up to.
+func (p Precision) granularity() time.Duration {
+	switch p {
+	case Coarse:
+		return 4 * time.Millisecond
+	case Lazy:
+		return 250 * time.Millisecond
+	}
+	return 0
+}
+
+// Deadline returns the time a timer of class p due at t actually fires: t
+// rounded up to the class's grid.
+func (p Precision) Deadline(t Time) Time {
+	return roundUp(t, p.granularity())
+}
+
+// roundUp returns t rounded up to a multiple of g, or t if g is not positive.
+func roundUp(t Time, g time.Duration) Time {
+	if g <= 0 {
+		return t
+	}
+	if r := t.Truncate(g); r != t {
+		return r.Add(g)
+	}
+	return t
+}
+
+var defaultPrecision int32 // Precision
+
+// SetDefaultPrecision sets the precision of timers created without one, such
+// as by NewTimerAt and the compat package, so an application can make its
+// timeouts coarser in one place. The default is Exact.
+func SetDefaultPrecision(p Precision) {
+	atomic.StoreInt32(&defaultPrecision, int32(p))
+}
+
+// DefaultPrecision returns the precision set by SetDefaultPrecision.
+func DefaultPrecision() Precision {
+	return Precision(atomic.LoadInt32(&defaultPrecision))
+}
@@ -0,0 +1,51 @@
+package monotime
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPrecisionDeadline(t *testing.T) {
+	for _, tt := range []struct {
+		p    Precision
+		t    Time
+		want Time
+	}{
+		{Exact, Time(time.Second + 1), Time(time.Second + 1)},
+		{Coarse, Time(time.Second + 1), Time(time.Second + 4*time.Millisecond)},
+		{Coarse, Time(time.Second), Time(time.Second)},
+		{Lazy, Time(time.Second + 1), Time(time.Second + 250*time.Millisecond)},
+	} {
+		if got := tt.p.Deadline(tt.t); got != tt.want {
+			t.Errorf("%v.Deadline(%d) = %d, want %d", tt.p, tt.t, got, tt.want)
+		}
+	}
+}
+
+func TestSetDefaultPrecision(t *testing.T) {
+	if got := DefaultPrecision(); got != Exact {
+		t.Fatalf("default precision %v, want exact", got)
+	}
+	SetDefaultPrecision(Lazy)
+	defer SetDefaultPrecision(Exact)
+	if got := DefaultPrecision(); got != Lazy {
+		t.Errorf("DefaultPrecision() = %v after setting lazy", got)
+	}
+
+	// Timers created without a precision now slide onto the lazy grid,
+	// but still report the deadline they were set for.
+	at := Now().Add(time.Millisecond)
+	timer := NewTimerAt(at)
+	defer timer.Stop()
+	select {
+	case got := <-timer.C:
+		if got != at {
+			t.Errorf("timer sent %v, want its deadline %v", got, at)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("lazy timer did not fire")
+	}
+	if now, grid := Now(), Lazy.Deadline(at); now.Before(grid) {
+		t.Errorf("lazy timer fired at %v, before its grid time %v", now, grid)
+	}
+}
@@ -0,0 +1,82 @@
+package monotime
+
+import (
+	"context"
+	"reflect"
+)
+
+// PrioritySelect waits on several tick sources, such as the C channels of
+// Tickers and Timers, and when more than one is ready delivers them in a
+// fixed priority order rather than at random as a select statement would. It
+// suits control loops where, say, a safety timer must always preempt a
+// housekeeping timer that fires at the same moment.
+//
+// A tick passed over for a higher priority source is held, not lost, and is
+// delivered by a later Wait. A PrioritySelect is not safe for concurrent use.
+type PrioritySelect struct {
+	sources []<-chan Time
+	pending []bool
+	cases   []reflect.SelectCase
+}
+
+// NewPrioritySelect returns a PrioritySelect over sources, highest priority
+// first. Nil sources are never ready.
+func NewPrioritySelect(sources ...<-chan Time) *PrioritySelect {
+	p := &PrioritySelect{
+		sources: append([]<-chan Time(nil), sources...),
+		pending: make([]bool, len(sources)),
+		cases:   make([]reflect.SelectCase, len(sources)+1),
+	}
+	for i, c := range p.sources {
+		p.cases[i] = reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(c)}
+	}
+	p.cases[len(sources)].Dir = reflect.SelectRecv
+	return p
+}
+
+// Wait blocks until a source is ready and returns the index of the highest
+// priority one that is, consuming its tick. It returns ctx.Err() if ctx is
+// done first.
+func (p *PrioritySelect) Wait(ctx context.Context) (int, error) {
+	if i, ok := p.ready(); ok {
+		return i, nil
+	}
+
+	// Block on every source without a held tick, and on ctx.
+	done := len(p.sources)
+	for i, c := range p.sources {
+		if p.pending[i] {
+			p.cases[i].Chan = reflect.Value{}
+		} else {
+			p.cases[i].Chan = reflect.ValueOf(c)
+		}
+	}
+	p.cases[done].Chan = reflect.ValueOf(ctx.Done())
+	chosen, _, _ := reflect.Select(p.cases)
+	if chosen == done {
+		return 0, ctx.Err()
+	}
+	p.pending[chosen] = true
+
+	// Sources that became ready at the same moment may have lost the
+	// race to wake us; look again, in order.
+	i, _ := p.ready()
+	return i, nil
+}
+
+// ready polls the sources in priority order and returns the first that is
+// ready or has a held tick.
+func (p *PrioritySelect) ready() (int, bool) {
+	for i, c := range p.sources {
+		if p.pending[i] {
+			p.pending[i] = false
+			return i, true
+		}
+		select {
+		case <-c:
+			return i, true
+		default:
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,57 @@
+package monotime
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestPrioritySelectOrder(t *testing.T) {
+	high, low := make(chan Time, 1), make(chan Time, 1)
+	p := NewPrioritySelect(high, low)
+	low <- Now()
+	high <- Now()
+
+	ctx := context.Background()
+	for _, want := range []int{0, 1} {
+		got, err := p.Wait(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("Wait() = %d, want %d", got, want)
+		}
+	}
+}
+
+func TestPrioritySelectHoldsTicks(t *testing.T) {
+	high, low := make(chan Time), make(chan Time)
+	p := NewPrioritySelect(high, nil, low)
+	go func() { low <- Now() }()
+	got, err := p.Wait(context.Background())
+	if err != nil || got != 2 {
+		t.Fatalf("Wait() = %d, %v, want 2", got, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := p.Wait(ctx); err != context.DeadlineExceeded {
+		t.Fatalf("Wait() with nothing ready = %v", err)
+	}
+}
+
+func TestPrioritySelectTickers(t *testing.T) {
+	// The C channels of every kind of ticker and timer are tick sources.
+	ticker := NewTicker(time.Millisecond)
+	defer ticker.Stop()
+	timer := NewTimer(time.Hour)
+	defer timer.Stop()
+	p := NewPrioritySelect(ticker.C, timer.C, After(time.Hour))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	got, err := p.Wait(ctx)
+	if err != nil || got != 0 {
+		t.Fatalf("Wait() = %d, %v, want the ticker at 0", got, err)
+	}
+}
@@ -0,0 +1,288 @@
+package monotime
+
+import (
+	"container/heap"
+	"encoding/json"
+	"io"
+	"sort"
+	"sync"
+	"time"
+)
+
+// QueuedTimer is an entry in a TimerQueue.
+type QueuedTimer struct {
+	// Key identifies the entry; scheduling a key again replaces it.
+	Key string
+	// Deadline is when the entry expires.
+	Deadline Time
+	// Data is opaque to the queue and is persisted along with the entry.
+	Data []byte
+}
+
+// TimerQueue holds any number of keyed deadlines, armed one at a time on a
+// kernel timer for the earliest, and delivers each entry on C when it
+// expires. Its pending entries can be saved and restored across process
+// restarts. It is safe for concurrent use.
+type TimerQueue struct {
+	// C receives entries as they expire, earliest first.
+	C <-chan QueuedTimer
+
+	c       chan QueuedTimer
+	changed chan struct{}
+	done    chan struct{}
+	exited  chan struct{}
+
+	closeOnce sync.Once
+
+	mu      sync.Mutex
+	entries queueHeap
+	byKey   map[string]*queueEntry
+}
+
+type queueEntry struct {
+	QueuedTimer
+	index int
+}
+
+// NewTimerQueue returns an empty TimerQueue. Close it to release its timer.
+func NewTimerQueue() *TimerQueue {
+	c := make(chan QueuedTimer)
+	q := &TimerQueue{
+		C:       c,
+		c:       c,
+		changed: make(chan struct{}, 1),
+		done:    make(chan struct{}),
+		exited:  make(chan struct{}),
+		byKey:   map[string]*queueEntry{},
+	}
+	go q.run()
+	return q
+}
+
+// Schedule adds an entry expiring at deadline, replacing any pending entry
+// with the same key.
+func (q *TimerQueue) Schedule(key string, deadline Time, data []byte) {
+	q.mu.Lock()
+	if e, ok := q.byKey[key]; ok {
+		e.Deadline, e.Data = deadline, data
+		heap.Fix(&q.entries, e.index)
+	} else {
+		e := &queueEntry{QueuedTimer: QueuedTimer{Key: key, Deadline: deadline, Data: data}}
+		heap.Push(&q.entries, e)
+		q.byKey[key] = e
+	}
+	q.mu.Unlock()
+	q.poke()
+}
+
+// Cancel removes the entry with the given key. It returns false if there was
+// no such pending entry.
+func (q *TimerQueue) Cancel(key string) bool {
+	q.mu.Lock()
+	e, ok := q.byKey[key]
+	if ok {
+		heap.Remove(&q.entries, e.index)
+		delete(q.byKey, key)
+	}
+	q.mu.Unlock()
+	if ok {
+		q.poke()
+	}
+	return ok
+}
+
+// Pending returns the pending entries, earliest first.
+func (q *TimerQueue) Pending() []QueuedTimer {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	pending := make([]QueuedTimer, len(q.entries))
+	for i, e := range q.entries {
+		pending[i] = e.QueuedTimer
+	}
+	sortQueued(pending)
+	return pending
+}
+
+// Close stops the queue. Pending entries are dropped; Save them first to keep
+// them.
+func (q *TimerQueue) Close() {
+	q.closeOnce.Do(func() { close(q.done) })
+	<-q.exited
+}
+
+func (q *TimerQueue) poke() {
+	select {
+	case q.changed <- struct{}{}:
+	default:
+	}
+}
+
+func (q *TimerQueue) run() {
+	defer close(q.exited)
+
+	// The timer is only replaced when the earliest deadline changes.
+	var timer *Timer
+	var armed Time
+	defer func() {
+		if timer != nil {
+			timer.Stop()
+		}
+	}()
+
+	for {
+		q.mu.Lock()
+		if len(q.entries) == 0 {
+			if timer != nil {
+				timer.Stop()
+				timer = nil
+			}
+		} else if next := q.entries[0].Deadline; timer == nil || next != armed {
+			if timer != nil {
+				timer.Stop()
+			}
+			timer, armed = NewTimerAt(next), next
+		}
+		q.mu.Unlock()
+
+		var fire <-chan Time
+		if timer != nil {
+			fire = timer.C
+		}
+		select {
+		case <-fire:
+			timer.Stop()
+			timer = nil
+		case <-q.changed:
+		case <-q.done:
+			return
+		}
+
+		for {
+			q.mu.Lock()
+			if len(q.entries) == 0 || q.entries[0].Deadline.After(Now()) {
+				q.mu.Unlock()
+				break
+			}
+			e := heap.Pop(&q.entries).(*queueEntry)
+			delete(q.byKey, e.Key)
+			q.mu.Unlock()
+
+			select {
+			case q.c <- e.QueuedTimer:
+			case <-q.done:
+				return
+			}
+		}
+	}
+}
+
+// savedQueue is the persisted form of a TimerQueue.
+type savedQueue struct {
+	// SavedAt is the monotonic time of the save, encoded with its boot ID
+	// as by Time.MarshalJSON. It is left raw so that a save from another
+	// boot can still be restored, from SavedWall.
+	SavedAt json.RawMessage
+	// BootID is set only by saves from before SavedAt carried the boot ID,
+	// when SavedAt was a bare reading.
+	BootID string `json:",omitempty"`
+	// SavedWall is the wall clock time of the save, in nanoseconds.
+	SavedWall int64
+	Entries   []savedEntry
+}
+
+type savedEntry struct {
+	Key string
+	// Remaining is the time left until the entry expires, as of the save.
+	Remaining time.Duration
+	Data      []byte
+}
+
+// Save writes the pending entries to w as JSON, as time remaining until
+// each expires together with the current boot ID, if the system has one.
+func (q *TimerQueue) Save(w io.Writer) error {
+	// Without a boot ID, Restore falls back on the wall clock.
+	now := Now()
+	at, err := now.MarshalJSON()
+	if err != nil {
+		return err
+	}
+	s := savedQueue{
+		SavedAt:   at,
+		SavedWall: time.Now().UnixNano(),
+	}
+	for _, e := range q.Pending() {
+		s.Entries = append(s.Entries, savedEntry{Key: e.Key, Remaining: e.Deadline.Sub(now), Data: e.Data})
+	}
+	return json.NewEncoder(w).Encode(s)
+}
+
+// Restore schedules the entries saved by Save, re-anchored to the current
+// monotonic clock.
+//
+// Within the same boot the monotonic clock kept running while the process was
+// down, so entries keep their exact original deadlines. After a reboot the
+// old monotonic times mean nothing; the downtime is then estimated from the
+// wall clock, and entries that would have expired during it expire at once.
+// On systems without a boot ID, such as Windows, a reboot can't be told
+// apart, so the wall clock is always used.
+func (q *TimerQueue) Restore(r io.Reader) error {
+	var s savedQueue
+	if err := json.NewDecoder(r).Decode(&s); err != nil {
+		return err
+	}
+	boot, _ := BootID()
+	anchor := s.anchor(boot)
+	for _, e := range s.Entries {
+		q.Schedule(e.Key, anchor.Add(e.Remaining), e.Data)
+	}
+	return nil
+}
+
+// anchor returns the current monotonic time of the save, for restoring under
+// boot ID boot.
+func (s *savedQueue) anchor(boot string) Time {
+	if boot != "" && s.BootID == boot {
+		var at int64
+		if err := json.Unmarshal(s.SavedAt, &at); err == nil {
+			return Time(at)
+		}
+	}
+	if boot != "" {
+		if at, err := decodeTime(s.SavedAt, boot); err == nil {
+			return at
+		}
+	}
+	down := time.Duration(time.Now().UnixNano() - s.SavedWall)
+	if down < 0 {
+		down = 0
+	}
+	return Now().Add(-down)
+}
+
+type queueHeap []*queueEntry
+
+func (h queueHeap) Len() int           { return len(h) }
+func (h queueHeap) Less(i, j int) bool { return h[i].Deadline.Before(h[j].Deadline) }
+func (h queueHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *queueHeap) Push(x interface{}) {
+	e := x.(*queueEntry)
+	e.index = len(*h)
+	*h = append(*h, e)
+}
+
+func (h *queueHeap) Pop() interface{} {
+	old := *h
+	e := old[len(old)-1]
+	old[len(old)-1] = nil
+	*h = old[:len(old)-1]
+	return e
+}
+
+func sortQueued(q []QueuedTimer) {
+	sort.Slice(q, func(i, j int) bool { return q[i].Deadline.Before(q[j].Deadline) })
+}
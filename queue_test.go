@@ -0,0 +1,141 @@
+package monotime
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestTimerQueue(t *testing.T) {
+	q := NewTimerQueue()
+	defer q.Close()
+	start := Now()
+	q.Schedule("late", start.Add(20*time.Millisecond), nil)
+	q.Schedule("early", start.Add(5*time.Millisecond), []byte("x"))
+	q.Schedule("cancelled", start.Add(time.Millisecond), nil)
+	if !q.Cancel("cancelled") {
+		t.Error("Cancel of a pending entry returned false")
+	}
+	if q.Cancel("cancelled") {
+		t.Error("second Cancel returned true")
+	}
+	for _, want := range []string{"early", "late"} {
+		select {
+		case e := <-q.C:
+			if e.Key != want {
+				t.Errorf("entry %q expired, want %q", e.Key, want)
+			}
+			if Now().Before(e.Deadline) {
+				t.Errorf("entry %q delivered before its deadline", e.Key)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("entry %q did not expire", want)
+		}
+	}
+	if pending := q.Pending(); len(pending) != 0 {
+		t.Errorf("%d entries still pending after expiring", len(pending))
+	}
+}
+
+func TestTimerQueueConcurrentClose(t *testing.T) {
+	q := NewTimerQueue()
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			q.Close()
+		}()
+	}
+	wg.Wait()
+}
+
+func TestTimerQueueSaveRestore(t *testing.T) {
+	q := NewTimerQueue()
+	deadline := Now().Add(time.Hour)
+	q.Schedule("a", deadline, []byte("payload"))
+	q.Schedule("b", deadline.Add(time.Minute), nil)
+	var buf bytes.Buffer
+	if err := q.Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+	q.Close()
+
+	r := NewTimerQueue()
+	defer r.Close()
+	if err := r.Restore(&buf); err != nil {
+		t.Fatal(err)
+	}
+	pending := r.Pending()
+	if len(pending) != 2 {
+		t.Fatalf("restored %d entries, want 2", len(pending))
+	}
+	if pending[0].Key != "a" || string(pending[0].Data) != "payload" {
+		t.Errorf("first restored entry %+v", pending[0])
+	}
+	// Within a boot, or falling back on the wall clock, the deadline is
+	// kept to well within a second.
+	if d := pending[0].Deadline.Sub(deadline); d < -time.Second || d > time.Second {
+		t.Errorf("restored deadline off by %v", d)
+	}
+}
+
+func TestSavedQueueAnchor(t *testing.T) {
+	now := Now()
+	at := now.Add(-time.Hour)
+	s := savedQueue{SavedAt: []byte(fmt.Sprintf(`{"ns":%d,"boot":"x"}`, at)), SavedWall: time.Now().Add(-time.Minute).UnixNano()}
+	if got := s.anchor("x"); got != now.Add(-time.Hour) {
+		t.Errorf("same boot: anchor %v, want the saved time %v", got, now.Add(-time.Hour))
+	}
+	for _, boot := range []string{"y", ""} {
+		// After a reboot, or without boot IDs, the wall clock says the
+		// save was a minute ago.
+		got := s.anchor(boot)
+		if d := Now().Add(-time.Minute).Sub(got); d < -time.Second || d > time.Second {
+			t.Errorf("boot %q: anchor off by %v", boot, d)
+		}
+	}
+	s.SavedAt = []byte(fmt.Sprintf(`{"ns":%d}`, at))
+	if got := s.anchor("x"); got == now.Add(-time.Hour) {
+		t.Error("saved without a boot ID: anchor trusted the monotonic time")
+	}
+	if got := s.anchor(""); got == now.Add(-time.Hour) {
+		t.Error("saved without a boot ID: anchor trusted the monotonic time")
+	}
+}
+
+func TestTimerQueueRestoreLegacy(t *testing.T) {
+	// Saves from before SavedAt carried its boot ID restore through the
+	// wall clock after a reboot.
+	legacy := func(boot string, at Time) string {
+		return fmt.Sprintf(`{"BootID":%q,"SavedAt":%d,"SavedWall":%d,"Entries":[{"Key":"a","Remaining":%d}]}`,
+			boot, at, time.Now().UnixNano(), time.Hour)
+	}
+	restore := func(saved string) Time {
+		q := NewTimerQueue()
+		defer q.Close()
+		if err := q.Restore(bytes.NewBufferString(saved)); err != nil {
+			t.Fatal(err)
+		}
+		pending := q.Pending()
+		if len(pending) != 1 {
+			t.Fatalf("restored %d entries, want 1", len(pending))
+		}
+		return pending[0].Deadline
+	}
+	if d := Until(restore(legacy("x", Now()))); d < time.Hour-time.Second || d > time.Hour {
+		t.Errorf("restored entry due in %v, want 1h", d)
+	}
+
+	// Within the same boot they keep their exact deadlines.
+	boot, err := BootID()
+	if err != nil || boot == "" {
+		t.Skip("no boot ID on this system")
+	}
+	at := Now().Add(-time.Minute)
+	if got, want := restore(legacy(boot, at)), at.Add(time.Hour); got != want {
+		t.Errorf("restored entry due at %v, want its original deadline %v", got, want)
+	}
+}
@@ -0,0 +1,7 @@
+package monotime
+
+// NowRaw returns the current raw time. Darwin never adjusts the rate of
+// mach_absolute_time, so this is the same reading as Now, typed as raw.
+func NowRaw() RawTime {
+	return RawTime(now())
+}
@@ -0,0 +1,8 @@
+package monotime
+
+import "golang.org/x/sys/unix"
+
+// NowRaw returns the current time on CLOCK_MONOTONIC_RAW.
+func NowRaw() RawTime {
+	return RawTime(readClock(unix.CLOCK_MONOTONIC_RAW))
+}
@@ -0,0 +1,23 @@
+//go:build darwin || linux || windows
+// +build darwin linux windows
+
+package monotime
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNowRaw(t *testing.T) {
+	raw, mono := NowRaw(), Now()
+	time.Sleep(10 * time.Millisecond)
+	dRaw, dMono := NowRaw().Sub(raw), Now().Sub(mono)
+	// NTP slews by at most 500ppm, far less than the scheduling noise
+	// allowed here.
+	if diff := dRaw - dMono; diff < -time.Millisecond || diff > time.Millisecond {
+		t.Errorf("raw clock advanced %v while the monotonic clock advanced %v", dRaw, dMono)
+	}
+	if got := raw.Add(time.Second).Sub(raw); got != time.Second {
+		t.Errorf("Add then Sub = %v", got)
+	}
+}
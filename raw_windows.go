@@ -0,0 +1,7 @@
+package monotime
+
+// NowRaw returns the current time on the performance counter, which Windows
+// never adjusts.
+func NowRaw() RawTime {
+	return RawTime(readQPC())
+}
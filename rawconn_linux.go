@@ -0,0 +1,36 @@
+package monotime
+
+import (
+	"errors"
+	"syscall"
+)
+
+// WithoutReader leaves reading the ticker's timerfd to the caller, who gets it
+// from SyscallConn to add to an event loop of their own: the ticker starts no
+// goroutine, sends nothing on C, and Pause, Resume and NextTick act on the
+// timerfd directly. Each read yields the number of expirations since the
+// last as a host-order uint64. Stop closes the descriptor, so the caller must
+// remove it from their poller first.
+func WithoutReader() TickerOption {
+	return func(cfg *tickerConfig) {
+		cfg.launch = func(kt *kernelTimer, fire func(n uint64) bool) error {
+			if kt.file == nil {
+				return errors.New("Error starting timer: WithoutReader needs a timerfd, not io_uring")
+			}
+			kt.startExternal()
+			return nil
+		}
+	}
+}
+
+// SyscallConn returns a raw connection to the ticker's timerfd, through which
+// its descriptor can be read or handed to another poller. Unless the ticker
+// was created WithoutReader, its own goroutine reads the same descriptor, and
+// whichever reads first takes the expirations. SyscallConn fails for a ticker
+// created WithIOUring, which has no timerfd.
+func (t *Ticker) SyscallConn() (syscall.RawConn, error) {
+	if t.kt.file == nil {
+		return nil, errors.New("Error getting ticker descriptor: timer is not a timerfd")
+	}
+	return t.kt.file.SyscallConn()
+}
@@ -0,0 +1,74 @@
+package monotime
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+func TestWithoutReader(t *testing.T) {
+	fds := openFDs(t)
+	ticker, err := NewTickerErr(time.Millisecond, WithoutReader())
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn, err := ticker.SyscallConn()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The read parks on the runtime's poller until the timerfd expires.
+	var n uint64
+	var buf [8]byte
+	err = conn.Read(func(fd uintptr) bool {
+		r, err := unix.Read(int(fd), buf[:])
+		if errors.Is(err, unix.EAGAIN) {
+			return false
+		}
+		if err != nil || r != len(buf) {
+			t.Fatalf("reading timerfd: %d bytes, %v", r, err)
+		}
+		n = hostOrder.Uint64(buf[:])
+		return true
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n == 0 {
+		t.Error("read no expirations")
+	}
+	select {
+	case <-ticker.C:
+		t.Error("tick sent with no reader")
+	default:
+	}
+
+	ticker.Stop()
+	waitFDs(t, fds)
+}
+
+func TestSyscallConn(t *testing.T) {
+	ticker := NewTicker(time.Hour)
+	defer ticker.Stop()
+	conn, err := ticker.SyscallConn()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.Control(func(fd uintptr) {
+		if int(fd) != ticker.kt.fd {
+			t.Errorf("descriptor %d, want the timerfd %d", fd, ticker.kt.fd)
+		}
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSyscallConnIOUring(t *testing.T) {
+	ticker := uringTicker(t, time.Hour)
+	defer ticker.Stop()
+	if _, err := ticker.SyscallConn(); err == nil {
+		t.Error("raw connection to an io_uring ticker")
+	}
+}
@@ -0,0 +1,27 @@
+package monotime
+
+import "time"
+
+// RawTime is a timestamp on the raw monotonic clock, which runs at the
+// hardware's own rate without the frequency adjustments NTP applies to Now's
+// clock. Durations between RawTimes are free of slewing, at the cost of the
+// oscillator's drift. It is a distinct type from Time because the two clocks
+// share no origin and drift apart: mixing them is a type error rather than a
+// silent bug.
+type RawTime int64
+
+// Add returns the raw time t+d.
+func (t RawTime) Add(d time.Duration) RawTime {
+	return t + RawTime(d)
+}
+
+// String returns t as the offset from the raw clock's origin in seconds, such
+// as "raw+8123.456789012s".
+func (t RawTime) String() string {
+	return formatReading("raw", int64(t))
+}
+
+// Sub returns the raw duration t-u.
+func (t RawTime) Sub(u RawTime) time.Duration {
+	return time.Duration(t - u)
+}
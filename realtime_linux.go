@@ -0,0 +1,95 @@
+package monotime
+
+import (
+	"errors"
+	"fmt"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// SchedPolicy is a Linux real-time scheduling policy.
+type SchedPolicy int
+
+// The real-time policies, with the kernel's values.
+const (
+	// SchedFIFO runs the thread until it blocks or yields, ahead of every
+	// thread of lower priority.
+	SchedFIFO SchedPolicy = 1
+	// SchedRR is SchedFIFO with time slicing among threads of equal
+	// priority.
+	SchedRR SchedPolicy = 2
+)
+
+func (p SchedPolicy) String() string {
+	switch p {
+	case SchedFIFO:
+		return "SCHED_FIFO"
+	case SchedRR:
+		return "SCHED_RR"
+	}
+	return fmt.Sprintf("SchedPolicy(%d)", int(p))
+}
+
+// schedParam is the kernel's struct sched_param.
+type schedParam struct {
+	priority int32
+}
+
+// EnterRealtime moves the calling thread to the real-time scheduling policy p
+// at the given priority, so the loop it runs preempts ordinary threads and
+// wakes from SleepUntil or a Ticker with as little delay as the kernel can
+// manage. The scheduling policy belongs to the OS thread, not the goroutine:
+// call runtime.LockOSThread first, and call the returned restore function,
+// which puts back the thread's previous policy, before unlocking.
+//
+// Unprivileged processes get a *CapabilityError for CAP_SYS_NICE, unless
+// RLIMIT_RTPRIO allows the priority. A real-time thread that never blocks
+// can starve the rest of the host, so keep such loops short and sleeping.
+func EnterRealtime(p SchedPolicy, priority int) (restore func() error, err error) {
+	if p != SchedFIFO && p != SchedRR {
+		return nil, fmt.Errorf("Error entering real-time scheduling: unknown policy %v", p)
+	}
+	lo, _, errno := unix.Syscall(unix.SYS_SCHED_GET_PRIORITY_MIN, uintptr(p), 0, 0)
+	if errno != 0 {
+		return nil, fmt.Errorf("Error getting %v priority range: %w", p, errno)
+	}
+	hi, _, errno := unix.Syscall(unix.SYS_SCHED_GET_PRIORITY_MAX, uintptr(p), 0, 0)
+	if errno != 0 {
+		return nil, fmt.Errorf("Error getting %v priority range: %w", p, errno)
+	}
+	if priority < int(lo) || priority > int(hi) {
+		return nil, fmt.Errorf("Error entering real-time scheduling: %v priority %d outside [%d, %d]", p, priority, lo, hi)
+	}
+
+	oldPolicy, _, errno := unix.RawSyscall(unix.SYS_SCHED_GETSCHEDULER, 0, 0, 0)
+	if errno != 0 {
+		return nil, fmt.Errorf("Error getting scheduling policy: %w", errno)
+	}
+	var old schedParam
+	if _, _, errno := unix.RawSyscall(unix.SYS_SCHED_GETPARAM, 0, uintptr(unsafe.Pointer(&old)), 0); errno != 0 {
+		return nil, fmt.Errorf("Error getting scheduling priority: %w", errno)
+	}
+
+	if err := setScheduler(int(p), schedParam{priority: int32(priority)}); err != nil {
+		if errors.Is(err, unix.EPERM) {
+			return nil, &CapabilityError{Op: fmt.Sprintf("set %v priority %d", p, priority), Capability: "CAP_SYS_NICE"}
+		}
+		return nil, fmt.Errorf("Error entering real-time scheduling: %w", err)
+	}
+	return func() error {
+		if err := setScheduler(int(oldPolicy), old); err != nil {
+			return fmt.Errorf("Error restoring scheduling policy: %w", err)
+		}
+		return nil
+	}, nil
+}
+
+// setScheduler sets the scheduling policy of the calling thread.
+func setScheduler(policy int, param schedParam) error {
+	_, _, errno := unix.RawSyscall(unix.SYS_SCHED_SETSCHEDULER, 0, uintptr(policy), uintptr(unsafe.Pointer(&param)))
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
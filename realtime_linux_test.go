@@ -0,0 +1,62 @@
+package monotime
+
+import (
+	"errors"
+	"runtime"
+	"testing"
+
+	"golang.org/x/sys/unix"
+)
+
+// schedPolicy returns the scheduling policy of the calling thread.
+func schedPolicy(t *testing.T) int {
+	p, _, errno := unix.RawSyscall(unix.SYS_SCHED_GETSCHEDULER, 0, 0, 0)
+	if errno != 0 {
+		t.Fatal(errno)
+	}
+	return int(p)
+}
+
+func TestEnterRealtime(t *testing.T) {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	before := schedPolicy(t)
+	restore, err := EnterRealtime(SchedFIFO, 1)
+	var capErr *CapabilityError
+	if errors.As(err, &capErr) {
+		if capErr.Capability != "CAP_SYS_NICE" {
+			t.Errorf("EnterRealtime asked for %s, want CAP_SYS_NICE", capErr.Capability)
+		}
+		t.Skip("no SCHED_FIFO without CAP_SYS_NICE:", err)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := schedPolicy(t); got != int(SchedFIFO) {
+		t.Errorf("policy %d after EnterRealtime, want SCHED_FIFO", got)
+	}
+	if err := restore(); err != nil {
+		t.Fatal(err)
+	}
+	if got := schedPolicy(t); got != before {
+		t.Errorf("policy %d after restore, want %d", got, before)
+	}
+}
+
+func TestEnterRealtimeInvalid(t *testing.T) {
+	if _, err := EnterRealtime(SchedPolicy(0), 1); err == nil {
+		t.Error("no error entering SCHED_OTHER as a real-time policy")
+	}
+	// Real-time priorities run from 1 to 99 on Linux.
+	if _, err := EnterRealtime(SchedRR, 100); err == nil {
+		t.Error("no error for a priority outside the policy's range")
+	}
+}
+
+func TestSchedPolicyString(t *testing.T) {
+	for p, want := range map[SchedPolicy]string{SchedFIFO: "SCHED_FIFO", SchedRR: "SCHED_RR", 7: "SchedPolicy(7)"} {
+		if got := p.String(); got != want {
+			t.Errorf("SchedPolicy(%d).String() = %q, want %q", int(p), got, want)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package monotime
+
+import (
+	"sync"
+	"time"
+)
+
+// relSchedule is the schedule of a kernel timer that can only be armed
+// relative to the present, like kqueue, Windows waitable timers and io_uring
+// timeouts. The
+// schedule is kept here, on the monotonic clock, and the kernel timer is
+// re-armed one-shot for the time left until each expiration, so expirations
+// stay on the grid at + n*interval however late each wakeup is.
+type relSchedule struct {
+	now func() Time // reads the clock the kernel timer runs on
+
+	mu       sync.Mutex
+	due      Time
+	interval time.Duration
+	armed    bool
+}
+
+// set starts the schedule at at, repeating every interval, or once if interval
+// is zero. It returns the time until the first expiration, which is always
+// positive.
+func (s *relSchedule) set(at Time, interval time.Duration) time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.due, s.interval, s.armed = at, interval, true
+	return s.wait()
+}
+
+// clear ends the schedule, so the next wakeup of the kernel timer counts no
+// expirations and does not re-arm it.
+func (s *relSchedule) clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.armed = false
+}
+
+// remaining returns the time until the next expiration, and false if the
+// schedule has ended. The expiration may be past, if the kernel timer's
+// wakeup has not been handled yet.
+func (s *relSchedule) remaining() (time.Duration, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.due.Sub(s.now()), s.armed
+}
+
+// expired accounts for a wakeup of the kernel timer. It returns how many
+// expirations have passed, and whether the kernel timer must be re-armed and
+// for how long.
+func (s *relSchedule) expired() (n uint64, rearm bool, wait time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.armed {
+		return 0, false, 0
+	}
+
+	now := s.now()
+	if now.Before(s.due) {
+		// Woken a little early by the kernel timer's own idea of
+		// time.
+		return 0, true, s.wait()
+	}
+	if s.interval == 0 {
+		s.armed = false
+		return 1, false, 0
+	}
+	n = uint64(now.Sub(s.due)/s.interval) + 1
+	s.due = s.due.Add(time.Duration(n) * s.interval)
+	return n, true, s.wait()
+}
+
+// wait returns the time until the next expiration; s.mu must be held.
+func (s *relSchedule) wait() time.Duration {
+	wait := s.due.Sub(s.now())
+	if wait < 1 {
+		wait = 1
+	}
+	return wait
+}
@@ -0,0 +1,56 @@
+//go:build !linux
+// +build !linux
+
+package monotime
+
+import (
+	"testing"
+	"time"
+)
+
+// fakeSchedule returns a relSchedule on a clock that only moves when told to.
+func fakeSchedule() (*relSchedule, *Time) {
+	now := new(Time)
+	*now = 1000
+	return &relSchedule{now: func() Time { return *now }}, now
+}
+
+func TestRelSchedulePeriodic(t *testing.T) {
+	s, now := fakeSchedule()
+	if wait := s.set(1100, 100); wait != 100 {
+		t.Errorf("first wait %v, want 100ns", wait)
+	}
+
+	// Woken early: nothing expired, re-arm for the rest.
+	*now = 1050
+	if n, rearm, wait := s.expired(); n != 0 || !rearm || wait != 50 {
+		t.Errorf("early wakeup: %d, %v, %v", n, rearm, wait)
+	}
+	// On time.
+	*now = 1100
+	if n, rearm, wait := s.expired(); n != 1 || !rearm || wait != 100 {
+		t.Errorf("on time: %d, %v, %v", n, rearm, wait)
+	}
+	// Late by more than two intervals: the expirations are counted, and
+	// the next stays on the grid.
+	*now = 1430
+	if n, rearm, wait := s.expired(); n != 3 || !rearm || wait != 70 {
+		t.Errorf("late: %d, %v, %v", n, rearm, wait)
+	}
+}
+
+func TestRelScheduleOneShot(t *testing.T) {
+	s, now := fakeSchedule()
+	// A time in the past still waits a little, so the kernel timer is
+	// armed rather than disarmed.
+	if wait := s.set(500, 0); wait != 1 {
+		t.Errorf("wait for a past time %v, want 1ns", wait)
+	}
+	if n, rearm, _ := s.expired(); n != 1 || rearm {
+		t.Errorf("one-shot: %d, %v", n, rearm)
+	}
+	*now += Time(time.Second)
+	if n, rearm, _ := s.expired(); n != 0 || rearm {
+		t.Errorf("after firing: %d, %v", n, rearm)
+	}
+}
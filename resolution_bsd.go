@@ -0,0 +1,34 @@
+//go:build dragonfly || freebsd || netbsd || openbsd
+// +build dragonfly freebsd netbsd openbsd
+
+package monotime
+
+import (
+	"fmt"
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// clockIDs maps each Clock to the kernel clock it reads. NowCoarse reads the
+// monotonic clock itself.
+var clockIDs = map[Clock]uintptr{
+	ClockMonotonic: clockMonotonic,
+	ClockCoarse:    clockMonotonic,
+}
+
+// Resolution returns the resolution the kernel advertises for clock c through
+// clock_getres: the smallest step between two of its readings. Readings
+// closer together than this cannot be told apart.
+func Resolution(c Clock) (time.Duration, error) {
+	id, ok := clockIDs[c]
+	if !ok {
+		return 0, errNoClock(c)
+	}
+	var ts unix.Timespec
+	if _, _, errno := unix.Syscall(unix.SYS_CLOCK_GETRES, id, uintptr(unsafe.Pointer(&ts)), 0); errno != 0 {
+		return 0, fmt.Errorf("Error getting resolution of the %v clock from the kernel: %w", c, errno)
+	}
+	return time.Duration(ts.Nano()), nil
+}
@@ -0,0 +1,19 @@
+package monotime
+
+import "time"
+
+// Resolution returns the resolution of clock c: the smallest step between two
+// of its readings, so that readings closer together cannot be told apart.
+// Darwin advertises none for its clocks, so it is measured from a burst of
+// readings. It is usually 1ns on Intel Macs and 41ns on Apple silicon.
+func Resolution(c Clock) (time.Duration, error) {
+	switch c {
+	case ClockMonotonic, ClockRaw, ClockCoarse:
+		return measureResolution(Now), nil
+	case ClockBoottime:
+		return measureResolution(NowBoottime), nil
+	case ClockProcessCPU:
+		return measureResolution(NowProcessCPU), nil
+	}
+	return 0, errNoClock(c)
+}
@@ -0,0 +1,34 @@
+package monotime
+
+import (
+	"fmt"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// clockIDs maps each Clock to the kernel clock it reads.
+var clockIDs = map[Clock]int32{
+	ClockMonotonic:     unix.CLOCK_MONOTONIC,
+	ClockRaw:           unix.CLOCK_MONOTONIC_RAW,
+	ClockCoarse:        unix.CLOCK_MONOTONIC_COARSE,
+	ClockBoottime:      unix.CLOCK_BOOTTIME,
+	ClockTAI:           unix.CLOCK_TAI,
+	ClockProcessCPU:    unix.CLOCK_PROCESS_CPUTIME_ID,
+	ClockBoottimeAlarm: unix.CLOCK_BOOTTIME_ALARM,
+}
+
+// Resolution returns the resolution the kernel advertises for clock c through
+// clock_getres: the smallest step between two of its readings. Readings
+// closer together than this cannot be told apart.
+func Resolution(c Clock) (time.Duration, error) {
+	id, ok := clockIDs[c]
+	if !ok {
+		return 0, errNoClock(c)
+	}
+	var ts unix.Timespec
+	if err := unix.ClockGetres(id, &ts); err != nil {
+		return 0, fmt.Errorf("Error getting resolution of the %v clock from the kernel: %w", c, err)
+	}
+	return time.Duration(ts.Nano()), nil
+}
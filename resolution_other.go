@@ -0,0 +1,18 @@
+//go:build !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd && !windows
+// +build !darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd,!windows
+
+package monotime
+
+import "time"
+
+// Resolution returns the resolution of clock c: the smallest step between two
+// of its readings, so that readings closer together cannot be told apart. The
+// host advertises none, so it is measured from a burst of readings. Browsers
+// coarsen performance.now to between 5µs and 100µs against timing attacks.
+func Resolution(c Clock) (time.Duration, error) {
+	switch c {
+	case ClockMonotonic, ClockCoarse:
+		return measureResolution(Now), nil
+	}
+	return 0, errNoClock(c)
+}
@@ -0,0 +1,29 @@
+package monotime
+
+import (
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestResolution(t *testing.T) {
+	res, err := Resolution(ClockMonotonic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res <= 0 || res > 100*time.Millisecond {
+		t.Errorf("Resolution(ClockMonotonic) = %v", res)
+	}
+	coarse, err := Resolution(ClockCoarse)
+	if err != nil {
+		t.Error(err)
+	}
+	// Where resolutions are measured rather than advertised, two
+	// measurements of the same clock can differ.
+	if advertised := runtime.GOOS == "linux" || runtime.GOOS == "windows"; advertised && coarse < res {
+		t.Errorf("coarse resolution %v is finer than monotonic %v", coarse, res)
+	}
+	if _, err := Resolution(Clock(-1)); err == nil {
+		t.Error("Resolution of an unknown clock succeeded")
+	}
+}
@@ -0,0 +1,113 @@
+package monotime
+
+import (
+	"fmt"
+	"sync"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+var (
+	winmm               = syscall.NewLazyDLL("winmm.dll")
+	procTimeGetDevCaps  = winmm.NewProc("timeGetDevCaps")
+	procTimeBeginPeriod = winmm.NewProc("timeBeginPeriod")
+	procTimeEndPeriod   = winmm.NewProc("timeEndPeriod")
+
+	procGetSystemTimeAdjustment = kernel32.NewProc("GetSystemTimeAdjustment")
+)
+
+// timecaps is the TIMECAPS structure filled in by timeGetDevCaps.
+type timecaps struct {
+	periodMin uint32
+	periodMax uint32
+}
+
+var (
+	resMu     sync.Mutex
+	resUsers  int
+	resPeriod uint32
+)
+
+// HighResolution raises the system timer resolution to the finest period the
+// host supports, usually 1ms, until the returned end function is called.
+// Windows otherwise services timers on a 15.6ms interrupt, so short intervals
+// fire late, but a raised resolution costs the whole host power and CPU, so
+// it should be held only while precise timers are in use.
+//
+// Requests are counted across the process: the resolution is raised on the
+// first and restored once every end function has been called. Calling an end
+// function more than once has no further effect.
+func HighResolution() (end func(), err error) {
+	resMu.Lock()
+	defer resMu.Unlock()
+
+	if resUsers == 0 {
+		var tc timecaps
+		if r, _, _ := procTimeGetDevCaps.Call(uintptr(unsafe.Pointer(&tc)), unsafe.Sizeof(tc)); r != 0 {
+			return nil, fmt.Errorf("Error getting timer capabilities: MMRESULT %d", r)
+		}
+		if r, _, _ := procTimeBeginPeriod.Call(uintptr(tc.periodMin)); r != 0 {
+			return nil, fmt.Errorf("Error raising timer resolution to %dms: MMRESULT %d", tc.periodMin, r)
+		}
+		resPeriod = tc.periodMin
+	}
+	resUsers++
+
+	var once sync.Once
+	return func() {
+		once.Do(releaseResolution)
+	}, nil
+}
+
+func releaseResolution() {
+	resMu.Lock()
+	defer resMu.Unlock()
+	resUsers--
+	if resUsers == 0 {
+		procTimeEndPeriod.Call(uintptr(resPeriod))
+	}
+}
+
+// Resolution returns the resolution of clock c: the smallest step between two
+// of its readings, so that readings closer together cannot be told apart.
+// The performance counter's is its period. The interrupt time NowCoarse reads,
+// and the process CPU time, advance once per clock interrupt; their resolution
+// is the default interrupt period, usually 15.6ms, even while HighResolution
+// is in effect.
+func Resolution(c Clock) (time.Duration, error) {
+	switch c {
+	case ClockMonotonic:
+		if d := qpcResolution(); !hasUnbiasedTime || d > 100 {
+			return d, nil
+		}
+		// The interrupt time is kept in 100ns units.
+		return 100, nil
+	case ClockRaw, ClockBoottime:
+		return qpcResolution(), nil
+	case ClockCoarse:
+		if !hasUnbiasedTime {
+			return qpcResolution(), nil
+		}
+		return interruptPeriod()
+	case ClockProcessCPU:
+		return interruptPeriod()
+	}
+	return 0, errNoClock(c)
+}
+
+// interruptPeriod returns the default period of the clock interrupt.
+func interruptPeriod() (time.Duration, error) {
+	var adjustment, increment uint32
+	var disabled int32
+	if r, _, err := procGetSystemTimeAdjustment.Call(uintptr(unsafe.Pointer(&adjustment)), uintptr(unsafe.Pointer(&increment)), uintptr(unsafe.Pointer(&disabled))); r == 0 {
+		return 0, fmt.Errorf("Error getting clock interrupt period from the kernel: %w", err)
+	}
+	return time.Duration(increment) * 100, nil
+}
+
+// qpcResolution returns the period of the performance counter, rounded up to
+// a whole nanosecond.
+func qpcResolution() time.Duration {
+	return (time.Second + time.Duration(qpcFrequency) - 1) / time.Duration(qpcFrequency)
+}
@@ -0,0 +1,67 @@
+package monotime
+
+import (
+	"fmt"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// sampleAttempts is how many times SampleClocks reads the clocks before
+// settling on the tightest sample. Preemption or an interrupt between reads
+// widens a sample; a handful of attempts is almost always enough to get one
+// that wasn't disturbed.
+const sampleAttempts = 5
+
+// ClockSample is a set of readings of different clocks taken as close
+// together as possible, for converting between them and measuring their
+// drift.
+type ClockSample struct {
+	// Monotonic is the CLOCK_MONOTONIC reading, taken as the midpoint of
+	// two readings bracketing the others.
+	Monotonic Time
+	// Raw is the CLOCK_MONOTONIC_RAW reading, which is not subject to NTP
+	// frequency adjustment.
+	Raw RawTime
+	// Realtime is the CLOCK_REALTIME reading. It carries no monotonic
+	// reading of its own.
+	Realtime time.Time
+	// Spread is the time between the bracketing CLOCK_MONOTONIC readings:
+	// an upper bound on how far apart the readings in the sample are.
+	Spread time.Duration
+}
+
+// SampleClocks reads CLOCK_MONOTONIC, CLOCK_MONOTONIC_RAW and CLOCK_REALTIME
+// back to back, several times, and returns the sample with the smallest
+// spread.
+func SampleClocks() ClockSample {
+	var best ClockSample
+	for i := 0; i < sampleAttempts; i++ {
+		before := now()
+		raw := NowRaw()
+		wall := readClock(unix.CLOCK_REALTIME)
+		after := now()
+
+		spread := after.Sub(before)
+		if i == 0 || spread < best.Spread {
+			best = ClockSample{
+				Monotonic: before.Add(spread / 2),
+				Raw:       raw,
+				Realtime:  time.Unix(0, int64(wall)),
+				Spread:    spread,
+			}
+		}
+	}
+	return best
+}
+
+// readClock reads the given kernel clock, panicking if it can't.
+func readClock(clockid int32) Time {
+	spec := new(unix.Timespec)
+	err := unix.ClockGettime(clockid, spec)
+	if err != nil {
+		err = fmt.Errorf("Error reading clock %d from the kernel: %w", clockid, err)
+		panic(err)
+	}
+	return Time(spec.Nano())
+}
@@ -0,0 +1,101 @@
+package monotime
+
+import (
+	"math/rand"
+	"time"
+)
+
+// Schedule is a sequence of monotonic times in ascending order. Next
+// returns the next time, or ok == false once the schedule is exhausted.
+type Schedule interface {
+	Next() (t Time, ok bool)
+}
+
+// timeList is a Schedule over a sorted slice of times.
+type timeList []Time
+
+func (l *timeList) Next() (Time, bool) {
+	if len(*l) == 0 {
+		return 0, false
+	}
+	t := (*l)[0]
+	*l = (*l)[1:]
+	return t, true
+}
+
+// Recurrence describes a periodic schedule. Build one by starting from Every
+// and chaining the other methods, e.g.
+//
+//	Every(time.Minute).StartingAt(t).Times(10).WithJitter(time.Second)
+//
+// A Recurrence is an immutable value; each method returns a modified copy.
+// Call Schedule to begin iterating its times.
+type Recurrence struct {
+	every    time.Duration
+	start    Time
+	hasStart bool
+	times    int
+	jitter   time.Duration
+}
+
+// Every returns a Recurrence that repeats every d, forever, starting d from
+// when Schedule is called. d must be greater than zero; if not, Every will
+// panic.
+func Every(d time.Duration) Recurrence {
+	if d <= 0 {
+		panic("non-positive interval for Every")
+	}
+	return Recurrence{every: d}
+}
+
+// StartingAt returns a copy of r whose first occurrence is at t.
+func (r Recurrence) StartingAt(t Time) Recurrence {
+	r.start, r.hasStart = t, true
+	return r
+}
+
+// Times returns a copy of r that stops after n occurrences. n <= 0 means
+// forever.
+func (r Recurrence) Times(n int) Recurrence {
+	r.times = n
+	return r
+}
+
+// WithJitter returns a copy of r in which each occurrence is delayed by a
+// random amount in [0, j). Jitter is applied to every occurrence
+// independently, so it never accumulates into drift. Jitter of d or more can
+// make occurrences collide; they are never reordered.
+func (r Recurrence) WithJitter(j time.Duration) Recurrence {
+	r.jitter = j
+	return r
+}
+
+// Schedule begins iterating the times of r.
+func (r Recurrence) Schedule() Schedule {
+	if !r.hasStart {
+		r.start = Now().Add(r.every)
+	}
+	return &recurrenceSchedule{r: r}
+}
+
+type recurrenceSchedule struct {
+	r    Recurrence
+	n    int
+	prev Time
+}
+
+func (s *recurrenceSchedule) Next() (Time, bool) {
+	if s.r.times > 0 && s.n >= s.r.times {
+		return 0, false
+	}
+	t := s.r.start.AddSat(mulSat(int64(s.n), s.r.every))
+	if s.r.jitter > 0 {
+		t = t.AddSat(time.Duration(rand.Int63n(int64(s.r.jitter))))
+	}
+	if s.n > 0 && t.Before(s.prev) {
+		t = s.prev
+	}
+	s.n++
+	s.prev = t
+	return t, true
+}
@@ -0,0 +1,92 @@
+package monotime
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// collect returns up to max times from s.
+func collect(s Schedule, max int) []Time {
+	var times []Time
+	for len(times) < max {
+		t, ok := s.Next()
+		if !ok {
+			break
+		}
+		times = append(times, t)
+	}
+	return times
+}
+
+func TestTimeList(t *testing.T) {
+	l := timeList{1, 2, 3}
+	got := collect(&l, 10)
+	if len(got) != 3 || got[0] != 1 || got[2] != 3 {
+		t.Errorf("got %v", got)
+	}
+	if _, ok := l.Next(); ok {
+		t.Error("exhausted list produced another time")
+	}
+}
+
+func TestRecurrence(t *testing.T) {
+	got := collect(Every(time.Second).StartingAt(100).Times(3).Schedule(), 10)
+	want := []Time{100, Time(100 + time.Second), Time(100 + 2*time.Second)}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("occurrence %d at %v, want %v", i, got[i], want[i])
+		}
+	}
+
+	// Without a start, the first occurrence is one interval from now.
+	before := Now()
+	first, _ := Every(time.Minute).Schedule().Next()
+	if first < before.Add(time.Minute) || first > Now().Add(time.Minute) {
+		t.Errorf("first occurrence %v from now", first.Sub(before))
+	}
+
+	// Times(0) repeats forever.
+	if n := len(collect(Every(1).StartingAt(0).Schedule(), 100)); n != 100 {
+		t.Errorf("unbounded recurrence ended after %d", n)
+	}
+}
+
+func TestRecurrenceSaturates(t *testing.T) {
+	const every = time.Duration(math.MaxInt64 / 2)
+	got := collect(Every(every).StartingAt(0).Times(5).Schedule(), 10)
+	want := []Time{0, Time(every), Time(2 * every), math.MaxInt64, math.MaxInt64}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("occurrence %d at %d, want %d", i, got[i], want[i])
+		}
+	}
+}
+
+func TestRecurrenceJitter(t *testing.T) {
+	const every, jitter = time.Second, 3 * time.Second
+	times := collect(Every(every).StartingAt(0).Times(200).WithJitter(jitter).Schedule(), 1000)
+	for i, tm := range times {
+		base := Time(time.Duration(i) * every)
+		if i > 0 && tm < times[i-1] {
+			t.Fatalf("occurrence %d at %v reordered before %v", i, tm, times[i-1])
+		}
+		// Occurrences are only ever held back to keep order, so each is
+		// within jitter of its base or of the one before.
+		if tm < base {
+			t.Errorf("occurrence %d at %v before its base %v", i, tm, base)
+		}
+	}
+}
+
+func TestEveryPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Every(0) did not panic")
+		}
+	}()
+	Every(0)
+}
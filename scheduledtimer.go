@@ -0,0 +1,80 @@
+package monotime
+
+import "sort"
+
+// ScheduledTimer fires once at each time of a Schedule, re-arming a single
+// kernel timer between shots. It suits schedules that are not a uniform
+// interval, such as retransmit back-off ladders.
+type ScheduledTimer struct {
+	// C receives the scheduled time of each shot as it fires.
+	C <-chan Time
+
+	kt *kernelTimer
+}
+
+// NewScheduledTimer returns a ScheduledTimer that fires at each of the given
+// times, in ascending order. Times that are not in the future fire
+// immediately, one after another.
+func NewScheduledTimer(at ...Time) *ScheduledTimer {
+	times := append(timeList(nil), at...)
+	sort.Slice(times, func(i, j int) bool { return times[i] < times[j] })
+	return NewScheduledTimerFrom(&times)
+}
+
+// NewScheduledTimerFrom returns a ScheduledTimer that fires at each time
+// produced by s, such as a Recurrence's Schedule. s is only used by the
+// timer's own goroutine. Once s is exhausted the kernel timer is released, so
+// a finished ScheduledTimer need not be stopped.
+func NewScheduledTimerFrom(s Schedule) *ScheduledTimer {
+	kt, err := newKernelTimer()
+	if err != nil {
+		panic(err)
+	}
+	next, ok := s.Next()
+	// An empty schedule still arms the timer, so that its goroutine
+	// wakes at once and releases it.
+	at := Now()
+	if ok {
+		at = next
+	}
+	if err := kt.arm(at, 0); err != nil {
+		kt.release()
+		panic(err)
+	}
+
+	c := make(chan Time, 1)
+	timer := &ScheduledTimer{C: c, kt: kt}
+	kt.start(func(uint64) bool {
+		if !ok {
+			return false
+		}
+		select {
+		case c <- next:
+		case <-kt.done:
+			return false
+		}
+		if next, ok = s.Next(); !ok {
+			// Exhausted: the goroutine exits and releases the timer.
+			return false
+		}
+		if err := kt.arm(next, 0); err != nil {
+			kt.fail(err)
+			return false
+		}
+		return true
+	})
+	return timer
+}
+
+// Stop cancels any shots that have not yet fired and releases the kernel
+// timer. Stop does not close the channel. Calling Stop more than once has no
+// further effect.
+func (t *ScheduledTimer) Stop() {
+	t.kt.stop()
+}
+
+// Err returns a channel that receives the error that ended the timer, should
+// its kernel timer fail, as Ticker.Err does.
+func (t *ScheduledTimer) Err() <-chan error {
+	return t.kt.errc
+}
@@ -0,0 +1,17 @@
+package monotime
+
+import "testing"
+
+func TestScheduledTimerReleasesWhenExhausted(t *testing.T) {
+	before := openFDs(t)
+	for i := 0; i < 20; i++ {
+		now := Now()
+		timer := NewScheduledTimer(now, now.Add(1))
+		<-timer.C
+		<-timer.C
+	}
+	for i := 0; i < 20; i++ {
+		NewScheduledTimer()
+	}
+	waitFDs(t, before)
+}
@@ -0,0 +1,27 @@
+package monotime
+
+import (
+	"testing"
+	"time"
+)
+
+func TestScheduledTimerOrder(t *testing.T) {
+	now := Now()
+	at := []Time{now.Add(3 * time.Millisecond), now.Add(time.Millisecond), now.Add(2 * time.Millisecond)}
+	timer := NewScheduledTimer(at...)
+	defer timer.Stop()
+	want := []Time{at[1], at[2], at[0]}
+	for _, w := range want {
+		select {
+		case got := <-timer.C:
+			if got != w {
+				t.Errorf("shot at %v, want %v", got, w)
+			}
+			if Now() < got {
+				t.Errorf("shot for %v fired early", got)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("shot did not fire")
+		}
+	}
+}
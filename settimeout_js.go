@@ -0,0 +1,146 @@
+package monotime
+
+import (
+	"math"
+	"sync"
+	"syscall/js"
+	"time"
+)
+
+var (
+	jsSetTimeout   = js.Global().Get("setTimeout")
+	jsClearTimeout = js.Global().Get("clearTimeout")
+)
+
+// kernelTimer is a timer together with the goroutine waiting on it. It is the
+// engine shared by Ticker and Timer; under JavaScript it is a setTimeout
+// callback, re-armed for each expiration. setInterval would be simpler, but it
+// drifts by its own rounding every period and can't start at an arbitrary
+// time, where re-arming keeps expirations on the grid.
+type kernelTimer struct {
+	sched relSchedule
+	cb    js.Func
+	fired chan struct{} // signalled by cb on the event loop
+
+	mu     sync.Mutex
+	id     js.Value // the pending timeout, if any
+	closed bool     // whether cb has been released
+
+	stopOnce sync.Once
+	done     chan struct{}
+	exited   chan struct{}
+	errc     chan error // receives the error that ended the goroutine, if any
+}
+
+// newKernelTimer returns a kernelTimer on the monotonic clock.
+func newKernelTimer() (*kernelTimer, error) {
+	f := &kernelTimer{
+		sched:  relSchedule{now: Now},
+		fired:  make(chan struct{}, 1),
+		done:   make(chan struct{}),
+		exited: make(chan struct{}),
+		errc:   make(chan error, 1),
+	}
+	f.cb = js.FuncOf(func(js.Value, []js.Value) interface{} {
+		// The event loop must not block: one pending signal is enough,
+		// since the schedule counts the expirations.
+		select {
+		case f.fired <- struct{}{}:
+		default:
+		}
+		return nil
+	})
+	return f, nil
+}
+
+// arm sets the timer to first expire at the monotonic time at, and then
+// every interval; an interval of zero makes it one-shot. Times that are not
+// in the future expire immediately.
+func (f *kernelTimer) arm(at Time, interval time.Duration) error {
+	return f.schedule(f.sched.set(at, interval))
+}
+
+// schedule sets a timeout to expire once, wait from now, replacing any
+// pending one.
+func (f *kernelTimer) schedule(wait time.Duration) error {
+	// Round up, so the timeout never fires before it is due.
+	ms := math.Ceil(float64(wait) / float64(time.Millisecond))
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.closed {
+		return nil
+	}
+	if f.id.Truthy() {
+		jsClearTimeout.Invoke(f.id)
+	}
+	f.id = jsSetTimeout.Invoke(f.cb, ms)
+	return nil
+}
+
+// disarm stops the timer expiring until it is armed again. A wakeup already
+// scheduled is left to find nothing due.
+func (f *kernelTimer) disarm() error {
+	f.sched.clear()
+	return nil
+}
+
+// remaining returns the time until the timer next expires, and false if it is
+// not armed.
+func (f *kernelTimer) remaining() (time.Duration, bool, error) {
+	left, armed := f.sched.remaining()
+	return left, armed, nil
+}
+
+// start runs fire on a new goroutine each time the timer expires, with the
+// number of expirations since the last call, until fire returns false or stop
+// is called. The timer is released when the goroutine exits.
+func (f *kernelTimer) start(fire func(n uint64) bool) {
+	go f.run(fire)
+}
+
+// stop ends the goroutine and releases the timer. Once stop returns fire is
+// not running and will not be called again. stop must not be called from
+// within fire.
+func (f *kernelTimer) stop() {
+	f.stopOnce.Do(func() {
+		close(f.done)
+		<-f.exited
+	})
+}
+
+// release clears the pending timeout and releases the callback of a timer
+// whose goroutine is not running. Calling release more than once has no
+// further effect.
+func (f *kernelTimer) release() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.closed {
+		return
+	}
+	f.closed = true
+	if f.id.Truthy() {
+		jsClearTimeout.Invoke(f.id)
+	}
+	f.cb.Release()
+}
+
+func (f *kernelTimer) run(fire func(n uint64) bool) {
+	defer close(f.exited)
+	defer f.release()
+
+	for {
+		select {
+		case <-f.fired:
+		case <-f.done:
+			return
+		}
+
+		expired, rearm, wait := f.sched.expired()
+		if rearm {
+			f.schedule(wait)
+		}
+		if expired > 0 && !fire(expired) {
+			return
+		}
+	}
+}
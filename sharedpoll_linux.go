@@ -0,0 +1,246 @@
+package monotime
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"golang.org/x/sys/unix"
+)
+
+// WithSharedPoller services the ticker's kernel timer from a pool of
+// goroutines shared by every ticker created with this option, waiting on one
+// epoll instance, instead of from a goroutine of its own. A ticker between
+// ticks then holds only its descriptor, so tens of thousands of them cost a
+// handful of threads; a tick waiting for its receiver still holds a goroutine
+// until it is received. NewTicker fails if the epoll instance can't be
+// created.
+func WithSharedPoller() TickerOption {
+	return func(cfg *tickerConfig) {
+		cfg.launch = func(kt *kernelTimer, fire func(n uint64) bool) error {
+			return kt.startShared(fire)
+		}
+	}
+}
+
+// sharedPollWorkers is the number of goroutines waiting on the shared epoll
+// instance. They only hand each expiration off to a goroutine of its own, so
+// a couple keep up with any number of timers.
+const sharedPollWorkers = 2
+
+// sharedPoller is the epoll instance behind WithSharedPoller. Each timerfd is
+// registered one-shot, so that until its expiration has been handled and it
+// is re-registered no other worker picks it up.
+type sharedPoller struct {
+	epfd int
+
+	mu     sync.Mutex
+	timers map[int32]*pooledTimer // by descriptor
+	err    error                  // why the workers stopped, if they have
+}
+
+var (
+	sharedMu sync.Mutex
+	shared   *sharedPoller // created on first use
+)
+
+// sharedPollerErr returns the shared poller, creating it and starting its
+// workers if this is the first use.
+func sharedPollerErr() (*sharedPoller, error) {
+	sharedMu.Lock()
+	defer sharedMu.Unlock()
+	if shared != nil {
+		return shared, nil
+	}
+	epfd, err := unix.EpollCreate1(unix.EPOLL_CLOEXEC)
+	if err != nil {
+		return nil, fmt.Errorf("Error creating epoll instance: %w", err)
+	}
+	shared = &sharedPoller{epfd: epfd, timers: make(map[int32]*pooledTimer)}
+	for i := 0; i < sharedPollWorkers; i++ {
+		go shared.wait()
+	}
+	return shared, nil
+}
+
+func (p *sharedPoller) wait() {
+	events := make([]unix.EpollEvent, 128)
+	for {
+		n, err := unix.EpollWait(p.epfd, events, -1)
+		if errors.Is(err, unix.EINTR) {
+			continue
+		}
+		if err != nil {
+			p.failAll(fmt.Errorf("Error waiting on epoll instance: %w", err))
+			return
+		}
+		p.mu.Lock()
+		for _, ev := range events[:n] {
+			if pt := p.timers[ev.Fd]; pt != nil {
+				go pt.service()
+			}
+		}
+		p.mu.Unlock()
+	}
+}
+
+// failAll retires the poller after waiting on it has failed with err, so that
+// later timers get a new one, and fails each timer registered on it. A timer
+// whose expiration is being handled fails when it is next re-registered.
+func (p *sharedPoller) failAll(err error) {
+	sharedMu.Lock()
+	if shared == p {
+		shared = nil
+	}
+	sharedMu.Unlock()
+
+	p.mu.Lock()
+	if p.err == nil {
+		p.err = err
+	}
+	timers := make([]*pooledTimer, 0, len(p.timers))
+	for _, pt := range p.timers {
+		timers = append(timers, pt)
+	}
+	p.mu.Unlock()
+	for _, pt := range timers {
+		pt.mu.Lock()
+		if !pt.busy && !pt.finished {
+			pt.kt.fail(err)
+			pt.finish()
+		}
+		pt.mu.Unlock()
+	}
+}
+
+// register adds or, with op EPOLL_CTL_MOD, re-arms the one-shot registration
+// of fd.
+func (p *sharedPoller) register(op, fd int) error {
+	p.mu.Lock()
+	err := p.err
+	p.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	ev := unix.EpollEvent{Events: unix.EPOLLIN | unix.EPOLLONESHOT, Fd: int32(fd)}
+	if err := unix.EpollCtl(p.epfd, op, fd, &ev); err != nil {
+		return fmt.Errorf("Error registering timerfd with epoll instance: %w", err)
+	}
+	return nil
+}
+
+// pooledTimer is a kernelTimer serviced by the shared poller.
+type pooledTimer struct {
+	kt   *kernelTimer
+	fire func(n uint64) bool
+	p    *sharedPoller
+
+	mu       sync.Mutex
+	busy     bool // an expiration is being handled
+	finished bool // the timer is released and its exited channel closed
+}
+
+// startShared is like start, but has the shared poller call fire. If the
+// timer can't be registered it is not started and must be released.
+func (f *kernelTimer) startShared(fire func(n uint64) bool) error {
+	if f.ring != nil {
+		return errors.New("Error starting timer: the shared poller needs a timerfd, not io_uring")
+	}
+	p, err := sharedPollerErr()
+	if err != nil {
+		return err
+	}
+	pt := &pooledTimer{kt: f, fire: fire, p: p}
+	f.pooled = pt
+	p.mu.Lock()
+	p.timers[int32(f.fd)] = pt
+	p.mu.Unlock()
+	if err := p.register(unix.EPOLL_CTL_ADD, f.fd); err != nil {
+		p.mu.Lock()
+		delete(p.timers, int32(f.fd))
+		p.mu.Unlock()
+		f.pooled = nil
+		return err
+	}
+	return nil
+}
+
+// service handles an expiration of the timer and re-registers it, unless
+// fire has asked to stop or the timer is being stopped.
+func (pt *pooledTimer) service() {
+	pt.mu.Lock()
+	if pt.busy || pt.finished {
+		// A stale event, for a descriptor since reused or already
+		// being handled.
+		pt.mu.Unlock()
+		return
+	}
+	pt.busy = true
+	pt.mu.Unlock()
+
+	more := pt.expire()
+
+	pt.mu.Lock()
+	defer pt.mu.Unlock()
+	pt.busy = false
+	select {
+	case <-pt.kt.done:
+		more = false
+	default:
+	}
+	if more {
+		if err := pt.p.register(unix.EPOLL_CTL_MOD, pt.kt.fd); err != nil {
+			pt.kt.fail(err)
+			more = false
+		}
+	}
+	if !more {
+		pt.finish()
+	}
+}
+
+// expire reads the timer's expirations and passes them to fire. It reports
+// whether to wait for more.
+func (pt *pooledTimer) expire() bool {
+	var buf [8]byte
+	r, err := unix.Read(pt.kt.fd, buf[:])
+	if errors.Is(err, unix.EAGAIN) || errors.Is(err, unix.EINTR) {
+		// Re-armed since it became readable.
+		return true
+	}
+	if err != nil {
+		pt.kt.fail(fmt.Errorf("Error reading timerfd: %w", err))
+		return false
+	}
+	if r != len(buf) {
+		pt.kt.fail(fmt.Errorf("Error reading timerfd: read %d bytes of %d", r, len(buf)))
+		return false
+	}
+	return pt.fire(hostOrder.Uint64(buf[:]))
+}
+
+// stop finishes the timer now if no expiration is being handled, and
+// otherwise leaves it to the handler, which sees the timer's done channel
+// closed; pt.kt.done must be closed already.
+func (pt *pooledTimer) stop() {
+	pt.mu.Lock()
+	defer pt.mu.Unlock()
+	if !pt.busy {
+		pt.finish()
+	}
+}
+
+// finish removes the timer from the shared poller, releases it and marks it
+// exited; pt.mu must be held.
+func (pt *pooledTimer) finish() {
+	if pt.finished {
+		return
+	}
+	pt.finished = true
+	pt.p.mu.Lock()
+	delete(pt.p.timers, int32(pt.kt.fd))
+	pt.p.mu.Unlock()
+	unix.EpollCtl(pt.p.epfd, unix.EPOLL_CTL_DEL, pt.kt.fd, nil)
+	pt.kt.release()
+	close(pt.kt.exited)
+}
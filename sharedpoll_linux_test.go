@@ -0,0 +1,110 @@
+package monotime
+
+import (
+	"errors"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestSharedPoller(t *testing.T) {
+	const n = 500
+	// The epoll instance, once created, stays open.
+	if _, err := sharedPollerErr(); err != nil {
+		t.Fatal(err)
+	}
+	before, fds := runtime.NumGoroutine(), openFDs(t)
+	tickers := make([]*Ticker, n)
+	for i := range tickers {
+		tickers[i] = NewTicker(time.Millisecond, WithSharedPoller())
+	}
+	for i, ticker := range tickers {
+		select {
+		case <-ticker.C:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("ticker %d did not tick", i)
+		}
+	}
+	for _, ticker := range tickers {
+		ticker.Stop()
+	}
+	waitFDs(t, fds)
+	if after := runtime.NumGoroutine(); after > before+sharedPollWorkers {
+		t.Errorf("%d goroutines after stopping every ticker, up from %d", after, before)
+	}
+}
+
+func TestSharedPollerIdle(t *testing.T) {
+	const n = 500
+	before := runtime.NumGoroutine()
+	for i := 0; i < n; i++ {
+		ticker := NewTicker(time.Hour, WithSharedPoller())
+		defer ticker.Stop()
+	}
+	if after := runtime.NumGoroutine(); after >= before+n {
+		t.Errorf("%d goroutines for %d idle tickers, up from %d", after, n, before)
+	}
+}
+
+func TestSharedPollerStopPending(t *testing.T) {
+	// A tick the receiver never takes holds a handler, which Stop must
+	// still end.
+	ticker := NewTicker(time.Millisecond, WithSharedPoller())
+	time.Sleep(10 * time.Millisecond)
+	stopped := make(chan struct{})
+	go func() {
+		ticker.Stop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Stop hung with a tick pending")
+	}
+}
+
+func TestSharedPollerFailure(t *testing.T) {
+	// A poller whose wait fails stops its worker rather than panicking.
+	broken := &sharedPoller{epfd: -1, timers: make(map[int32]*pooledTimer)}
+	exited := make(chan struct{})
+	go func() {
+		broken.wait()
+		close(exited)
+	}()
+	select {
+	case <-exited:
+	case <-time.After(5 * time.Second):
+		t.Fatal("wait on a bad descriptor did not return")
+	}
+	if broken.err == nil {
+		t.Error("failed poller recorded no error")
+	}
+
+	// Its timers fail, and later ones get a new poller.
+	ticker := NewTicker(time.Hour, WithSharedPoller())
+	defer ticker.Stop()
+	p := ticker.kt.pooled.p
+	p.failAll(errors.New("test failure"))
+	select {
+	case err := <-ticker.Err():
+		if err == nil || err.Error() != "test failure" {
+			t.Errorf("Err() = %v, want the poller's failure", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("ticker on a failed poller did not fail")
+	}
+	next, err := sharedPollerErr()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next == p {
+		t.Error("failed poller is still handed out")
+	}
+	other := NewTicker(time.Millisecond, WithSharedPoller())
+	defer other.Stop()
+	select {
+	case <-other.C:
+	case <-time.After(5 * time.Second):
+		t.Fatal("ticker on the new poller did not tick")
+	}
+}
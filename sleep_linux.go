@@ -0,0 +1,52 @@
+package monotime
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// Sleep pauses the current goroutine for at least d on the monotonic clock,
+// through clock_nanosleep on CLOCK_MONOTONIC, so the sleep is unaffected by
+// changes to the wall clock. If d is not positive Sleep returns immediately.
+// The sleep blocks an OS thread, which the runtime replaces while it lasts.
+func Sleep(d time.Duration) {
+	if d <= 0 {
+		return
+	}
+	spec := unix.NsecToTimespec(int64(d))
+	for {
+		var rem unix.Timespec
+		err := unix.ClockNanosleep(unix.CLOCK_MONOTONIC, 0, &spec, &rem)
+		if err == nil {
+			return
+		}
+		if !errors.Is(err, unix.EINTR) {
+			err = fmt.Errorf("Error sleeping on monotime: %w", err)
+			panic(err)
+		}
+		// Interrupted by a signal: sleep for what was left.
+		spec = rem
+	}
+}
+
+// SleepUntil pauses the current goroutine until the monotonic clock reaches
+// t. If t is not in the future SleepUntil returns immediately.
+//
+// Because the deadline is absolute, repeatedly sleeping until t.Add(d) does
+// not accumulate the drift that repeated relative sleeps would.
+func SleepUntil(t Time) {
+	spec := unix.NsecToTimespec(int64(t))
+	for {
+		err := unix.ClockNanosleep(unix.CLOCK_MONOTONIC, unix.TIMER_ABSTIME, &spec, nil)
+		if err == nil {
+			return
+		}
+		if !errors.Is(err, unix.EINTR) {
+			err = fmt.Errorf("Error sleeping on monotime: %w", err)
+			panic(err)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package monotime
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+	"testing"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// interruptEvery signals the process every d until the test ends. Each signal
+// interrupts a clock_nanosleep on whichever thread it lands.
+func interruptEvery(t *testing.T, d time.Duration) {
+	sigs := make(chan os.Signal, 16)
+	signal.Notify(sigs, syscall.SIGUSR1)
+	done := make(chan struct{})
+	t.Cleanup(func() {
+		close(done)
+		signal.Stop(sigs)
+	})
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case <-time.After(d):
+				unix.Kill(os.Getpid(), unix.SIGUSR1)
+			}
+		}
+	}()
+}
+
+func TestSleepInterrupted(t *testing.T) {
+	interruptEvery(t, 2*time.Millisecond)
+	start := Now()
+	Sleep(30 * time.Millisecond)
+	if d := Now().Sub(start); d < 30*time.Millisecond {
+		t.Errorf("interrupted Sleep(30ms) returned after %v", d)
+	}
+}
+
+func TestSleepUntilInterrupted(t *testing.T) {
+	interruptEvery(t, 2*time.Millisecond)
+	deadline := Now().Add(30 * time.Millisecond)
+	SleepUntil(deadline)
+	// Restarting the absolute sleep after each signal neither returns early
+	// nor oversleeps by the time already slept.
+	late := Now().Sub(deadline)
+	if late < 0 || late > 20*time.Millisecond {
+		t.Errorf("interrupted SleepUntil returned %v after its deadline", late)
+	}
+}
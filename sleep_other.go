@@ -0,0 +1,29 @@
+//go:build !linux
+// +build !linux
+
+package monotime
+
+import "time"
+
+// Sleep pauses the current goroutine for at least d on the monotonic clock. If
+// d is not positive Sleep returns immediately.
+func Sleep(d time.Duration) {
+	SleepUntil(Now().Add(d))
+}
+
+// SleepUntil pauses the current goroutine until the monotonic clock reaches
+// t. If t is not in the future SleepUntil returns immediately.
+//
+// Because the deadline is absolute, repeatedly sleeping until t.Add(d) does
+// not accumulate the drift that repeated relative sleeps would.
+func SleepUntil(t Time) {
+	// There is no absolute sleep to hand, so sleep relative to the
+	// deadline, again if the sleep came up short.
+	for {
+		d := Until(t)
+		if d <= 0 {
+			return
+		}
+		time.Sleep(d)
+	}
+}
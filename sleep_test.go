@@ -0,0 +1,33 @@
+package monotime
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSleepUntil(t *testing.T) {
+	deadline := Now().Add(2 * time.Millisecond)
+	SleepUntil(deadline)
+	if now := Now(); now < deadline {
+		t.Errorf("SleepUntil returned %v early", deadline.Sub(now))
+	}
+	// A deadline in the past returns at once.
+	start := Now()
+	SleepUntil(start.Add(-time.Second))
+	if d := Now().Sub(start); d > 100*time.Millisecond {
+		t.Errorf("SleepUntil in the past took %v", d)
+	}
+}
+
+func TestSleep(t *testing.T) {
+	start := Now()
+	Sleep(2 * time.Millisecond)
+	if d := Now().Sub(start); d < 2*time.Millisecond {
+		t.Errorf("Sleep(2ms) returned after %v", d)
+	}
+	start = Now()
+	Sleep(-time.Second)
+	if d := Now().Sub(start); d > 100*time.Millisecond {
+		t.Errorf("Sleep of a negative duration took %v", d)
+	}
+}
@@ -0,0 +1,97 @@
+package monotime
+
+import (
+	"errors"
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// Flags for SO_TIMESTAMPING, from linux/net_tstamp.h.
+const (
+	sofTimestampingTxHardware  = 1 << 0
+	sofTimestampingTxSoftware  = 1 << 1
+	sofTimestampingRxHardware  = 1 << 2
+	sofTimestampingRxSoftware  = 1 << 3
+	sofTimestampingSoftware    = 1 << 4
+	sofTimestampingRawHardware = 1 << 6
+)
+
+// SocketTimestamps are the timestamps the kernel attached to a packet, as
+// parsed from its control messages by ParseSocketTimestamps.
+type SocketTimestamps struct {
+	// Software is the kernel's software timestamp, converted from
+	// CLOCK_REALTIME to the monotonic clock. Zero if absent.
+	Software Time
+
+	// Hardware is the NIC's raw hardware timestamp in nanoseconds. It is
+	// on the NIC's own clock (usually a PTP hardware clock), so it cannot
+	// be converted to a Time; compare hardware timestamps with each other.
+	// Zero if absent.
+	Hardware time.Duration
+}
+
+// EnableSocketTimestamps turns on software receive and transmit timestamps,
+// and hardware timestamps where the NIC has been configured to produce them,
+// for the socket fd. Receive timestamps arrive as control messages on each
+// read; transmit timestamps arrive on the socket's error queue
+// (MSG_ERRQUEUE). Parse both with ParseSocketTimestamps.
+func EnableSocketTimestamps(fd int) error {
+	flags := sofTimestampingRxSoftware | sofTimestampingTxSoftware | sofTimestampingSoftware |
+		sofTimestampingRxHardware | sofTimestampingTxHardware | sofTimestampingRawHardware
+	return unix.SetsockoptInt(fd, unix.SOL_SOCKET, unix.SO_TIMESTAMPING, flags)
+}
+
+// ParseSocketTimestamps extracts the SCM_TIMESTAMPNS and SCM_TIMESTAMPING
+// timestamps from the control messages oob returned by recvmsg, e.g. via
+// (*net.UDPConn).ReadMsgUDP.
+//
+// The kernel stamps packets on CLOCK_REALTIME. The conversion to monotonic
+// time uses the current offset between the two clocks, so it is only exact if
+// the wall clock was not stepped between the packet arriving and the call.
+func ParseSocketTimestamps(oob []byte) (SocketTimestamps, error) {
+	msgs, err := unix.ParseSocketControlMessage(oob)
+	if err != nil {
+		return SocketTimestamps{}, err
+	}
+
+	var ts SocketTimestamps
+	var found bool
+	var sw unix.Timespec
+	for _, m := range msgs {
+		if m.Header.Level != unix.SOL_SOCKET {
+			continue
+		}
+		switch m.Header.Type {
+		case unix.SCM_TIMESTAMPNS:
+			if len(m.Data) < int(unsafe.Sizeof(unix.Timespec{})) {
+				return SocketTimestamps{}, errors.New("short SCM_TIMESTAMPNS control message")
+			}
+			sw = *(*unix.Timespec)(unsafe.Pointer(&m.Data[0]))
+			found = true
+		case unix.SCM_TIMESTAMPING:
+			// struct scm_timestamping { struct timespec ts[3]; }:
+			// software, legacy (unused), raw hardware.
+			var stamps [3]unix.Timespec
+			if len(m.Data) < int(unsafe.Sizeof(stamps)) {
+				return SocketTimestamps{}, errors.New("short SCM_TIMESTAMPING control message")
+			}
+			stamps = *(*[3]unix.Timespec)(unsafe.Pointer(&m.Data[0]))
+			if stamps[0].Nano() != 0 {
+				sw = stamps[0]
+			}
+			ts.Hardware = time.Duration(stamps[2].Nano())
+			found = true
+		}
+	}
+	if !found {
+		return SocketTimestamps{}, errors.New("no socket timestamp control message")
+	}
+
+	if sw.Nano() != 0 {
+		s := SampleClocks()
+		ts.Software = s.Monotonic.Add(time.Duration(sw.Nano() - s.Realtime.UnixNano()))
+	}
+	return ts, nil
+}
@@ -0,0 +1,94 @@
+package monotime
+
+import (
+	"net"
+	"testing"
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+func TestSocketTimestamps(t *testing.T) {
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Skip(err)
+	}
+	defer conn.Close()
+	raw, err := conn.SyscallConn()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := raw.Control(func(fd uintptr) { err = EnableSocketTimestamps(int(fd)) }); err != nil {
+		t.Fatal(err)
+	}
+	if err != nil {
+		t.Skipf("SO_TIMESTAMPING not supported: %v", err)
+	}
+
+	// The kernel turns timestamping on in deferred work, so the first
+	// packets may still arrive unstamped.
+	var sent Time
+	var ts SocketTimestamps
+	buf, oob := make([]byte, 16), make([]byte, 512)
+	for i := 0; ; i++ {
+		sent = Now()
+		if _, err := conn.WriteToUDP([]byte("x"), conn.LocalAddr().(*net.UDPAddr)); err != nil {
+			t.Fatal(err)
+		}
+		conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		_, oobn, _, _, err := conn.ReadMsgUDP(buf, oob)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ts, err = ParseSocketTimestamps(oob[:oobn]); err == nil {
+			break
+		}
+		if i == 10 {
+			t.Fatal(err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	// The software stamp converts to about when the packet was sent, to
+	// the precision of sampling the wall clock.
+	if d := ts.Software.Sub(sent); d < -time.Millisecond || d > time.Second {
+		t.Errorf("software timestamp %v from when the packet was sent", d)
+	}
+}
+
+// timestampingMessage returns a control message carrying an SCM_TIMESTAMPING
+// payload of the given software and raw hardware stamps.
+func timestampingMessage(sw, hw unix.Timespec) []byte {
+	stamps := [3]unix.Timespec{sw, {}, hw}
+	data := (*[unsafe.Sizeof(stamps)]byte)(unsafe.Pointer(&stamps))[:]
+	b := make([]byte, unix.CmsgSpace(len(data)))
+	h := (*unix.Cmsghdr)(unsafe.Pointer(&b[0]))
+	h.Level, h.Type = unix.SOL_SOCKET, unix.SCM_TIMESTAMPING
+	h.SetLen(unix.CmsgLen(len(data)))
+	copy(b[unix.CmsgLen(0):], data)
+	return b
+}
+
+func TestParseSocketTimestamps(t *testing.T) {
+	hw := unix.NsecToTimespec(int64(12 * time.Second))
+	ts, err := ParseSocketTimestamps(timestampingMessage(unix.Timespec{}, hw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ts.Hardware != 12*time.Second || !ts.Software.IsZero() {
+		t.Errorf("parsed %+v, want a hardware stamp of 12s and no software one", ts)
+	}
+
+	now := time.Now()
+	ts, err = ParseSocketTimestamps(timestampingMessage(unix.NsecToTimespec(now.UnixNano()), unix.Timespec{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := Since(ts.Software); d < -time.Millisecond || d > time.Second {
+		t.Errorf("software stamp of the present converted to %v ago", d)
+	}
+
+	if _, err := ParseSocketTimestamps(nil); err == nil {
+		t.Error("no error parsing no control messages")
+	}
+}
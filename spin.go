@@ -0,0 +1,77 @@
+package monotime
+
+import (
+	"runtime"
+	"sync"
+	"time"
+)
+
+// SpinTicker is a ticker that busy-polls the monotonic clock instead of
+// waiting on a kernel timer. Kernel timers cannot keep up with periods much
+// below 100µs: the wakeup alone costs a good part of the period. A
+// SpinTicker's goroutine never sleeps, so it delivers such periods
+// accurately, at the cost of keeping a CPU fully busy for as long as it
+// runs. Use it only for short-lived, very fast loops, and prefer Ticker for
+// everything else.
+type SpinTicker struct {
+	// C receives the due time of each tick. Ticks stay on the grid start +
+	// n*d; if the receiver falls behind, the missed ticks are delivered
+	// back to back.
+	C <-chan Time
+
+	stopOnce sync.Once
+	done     chan struct{}
+	exited   chan struct{}
+}
+
+// NewSpinTicker returns a SpinTicker whose first tick is d from now, and
+// every d after that. d must be greater than zero; if not, NewSpinTicker will
+// panic. Stop the ticker to free the CPU it spins on.
+func NewSpinTicker(d time.Duration) *SpinTicker {
+	if d <= 0 {
+		panic("non-positive interval for NewSpinTicker")
+	}
+	c := make(chan Time)
+	t := &SpinTicker{C: c, done: make(chan struct{}), exited: make(chan struct{})}
+	go t.run(c, Now().Add(d), d)
+	return t
+}
+
+// Stop turns off the ticker. Once Stop returns no more ticks will be sent and
+// the spinning goroutine has exited. Stop does not close the channel. Calling
+// Stop more than once has no further effect.
+func (t *SpinTicker) Stop() {
+	t.stopOnce.Do(func() {
+		close(t.done)
+		<-t.exited
+	})
+}
+
+func (t *SpinTicker) run(c chan<- Time, due Time, d time.Duration) {
+	defer close(t.exited)
+	for {
+		for {
+			left := Until(due)
+			if left <= 0 {
+				break
+			}
+			select {
+			case <-t.done:
+				return
+			default:
+			}
+			// Yield while the tick is far enough off, as SpinUntil
+			// does, so goroutines sharing the thread still get to
+			// run.
+			if left > spinYieldAbove {
+				runtime.Gosched()
+			}
+		}
+		select {
+		case c <- due:
+		case <-t.done:
+			return
+		}
+		due = due.Add(d)
+	}
+}
@@ -0,0 +1,56 @@
+package monotime
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSpinTicker(t *testing.T) {
+	const d = 50 * time.Microsecond
+	start := Now()
+	ticker := NewSpinTicker(d)
+	defer ticker.Stop()
+	for i := 1; i <= 20; i++ {
+		due := <-ticker.C
+		if want := start.Add(time.Duration(i) * d); due < want {
+			t.Errorf("tick %d due at %v, before the grid time %v", i, due, want)
+		}
+		if now := Now(); now < due {
+			t.Errorf("tick %d sent at %v, before it was due at %v", i, now, due)
+		}
+	}
+}
+
+func TestSpinTickerCatchUp(t *testing.T) {
+	const d = 100 * time.Microsecond
+	ticker := NewSpinTicker(d)
+	defer ticker.Stop()
+	first := <-ticker.C
+	time.Sleep(10 * d)
+	// The missed ticks come back to back, each still on the grid.
+	for i := 1; i <= 5; i++ {
+		if due, want := <-ticker.C, first.Add(time.Duration(i)*d); due != want {
+			t.Errorf("tick %d due at %v, want %v", i, due, want)
+		}
+	}
+}
+
+func TestSpinTickerStop(t *testing.T) {
+	ticker := NewSpinTicker(time.Hour)
+	ticker.Stop()
+	ticker.Stop()
+	select {
+	case <-ticker.C:
+		t.Fatal("tick after Stop")
+	case <-time.After(10 * time.Millisecond):
+	}
+}
+
+func TestNewSpinTickerPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("NewSpinTicker(0) did not panic")
+		}
+	}()
+	NewSpinTicker(0)
+}
@@ -0,0 +1,272 @@
+// Package statsd ships monotime measurements to a StatsD or DogStatsD agent
+// over UDP.
+//
+// An Emitter collects registered histograms, counters and tickers and flushes
+// them on a monotonic Ticker, so the reporting interval holds steady through
+// wall clock steps. Histograms are reported as gauges of their quantiles, in
+// milliseconds, over each interval; counters as StatsD counters, from which
+// the agent derives rates.
+package statsd
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/thisguycodes/monotime"
+)
+
+// maxPacket keeps each datagram within the payload of a 1500 byte MTU, the
+// size StatsD agents expect.
+const maxPacket = 1432
+
+// Config configures an Emitter.
+type Config struct {
+	// Addr is the agent's UDP address. The default is "127.0.0.1:8125".
+	Addr string
+
+	// Prefix is prepended to every metric name, e.g. "myapp.".
+	Prefix string
+
+	// Tags are DogStatsD tags added to every metric, e.g. "env:prod".
+	// Leave it empty for plain StatsD.
+	Tags []string
+
+	// Interval is the flush period. The default is 10 seconds.
+	Interval time.Duration
+
+	// Quantiles are the quantiles reported for each histogram. The
+	// default is 0.5, 0.9 and 0.99.
+	Quantiles []float64
+
+	// OnError, if set, is called with the error of each failed periodic
+	// flush. Otherwise such errors are dropped, as is usual for StatsD.
+	OnError func(error)
+}
+
+// Emitter periodically sends registered metrics to a StatsD agent. It is safe
+// for concurrent use.
+type Emitter struct {
+	cfg  Config
+	conn net.Conn
+	tags string
+
+	mu         sync.Mutex
+	histograms map[string]*monotime.Histogram
+	counters   map[string]*Counter
+	tickers    map[string]*tickerStats
+
+	ticker *monotime.Ticker
+	quit   chan struct{}
+	exited chan struct{}
+
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// Counter is a count reported to the agent as a StatsD counter.
+type Counter struct {
+	n int64 // atomic
+}
+
+// Add adds n to the counter.
+func (c *Counter) Add(n int64) {
+	atomic.AddInt64(&c.n, n)
+}
+
+// Inc adds one to the counter.
+func (c *Counter) Inc() {
+	c.Add(1)
+}
+
+type tickerStats struct {
+	t       *monotime.Ticker
+	skipped uint64
+}
+
+// New returns an Emitter sending to the agent cfg describes, flushing every
+// cfg.Interval until closed.
+func New(cfg Config) (*Emitter, error) {
+	if cfg.Addr == "" {
+		cfg.Addr = "127.0.0.1:8125"
+	}
+	if cfg.Interval == 0 {
+		cfg.Interval = 10 * time.Second
+	}
+	if cfg.Interval < 0 {
+		return nil, fmt.Errorf("negative statsd flush interval %v", cfg.Interval)
+	}
+	if cfg.Quantiles == nil {
+		cfg.Quantiles = []float64{0.5, 0.9, 0.99}
+	}
+	for _, q := range cfg.Quantiles {
+		if q < 0 || q > 1 {
+			return nil, fmt.Errorf("statsd quantile %v outside [0, 1]", q)
+		}
+	}
+
+	conn, err := net.Dial("udp", cfg.Addr)
+	if err != nil {
+		return nil, fmt.Errorf("Error dialing statsd agent: %w", err)
+	}
+	e := &Emitter{
+		cfg:        cfg,
+		conn:       conn,
+		histograms: make(map[string]*monotime.Histogram),
+		counters:   make(map[string]*Counter),
+		tickers:    make(map[string]*tickerStats),
+		ticker:     monotime.NewTicker(cfg.Interval),
+		quit:       make(chan struct{}),
+		exited:     make(chan struct{}),
+	}
+	if len(cfg.Tags) > 0 {
+		e.tags = "|#" + strings.Join(cfg.Tags, ",")
+	}
+	go e.run()
+	return e, nil
+}
+
+// Histogram registers h to be reported under name. Each flush drains h, so
+// the quantiles sent describe the durations recorded since the last flush.
+func (e *Emitter) Histogram(name string, h *monotime.Histogram) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.histograms[name] = h
+}
+
+// Counter returns the counter reported under name, creating it on first use.
+func (e *Emitter) Counter(name string) *Counter {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	c, ok := e.counters[name]
+	if !ok {
+		c = new(Counter)
+		e.counters[name] = c
+	}
+	return c
+}
+
+// Ticker registers t to have its skipped ticks, see monotime.WithMaxCatchUp,
+// reported under name + ".skipped".
+func (e *Emitter) Ticker(name string, t *monotime.Ticker) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.tickers[name] = &tickerStats{t: t, skipped: t.Skipped()}
+}
+
+// Flush sends every registered metric now.
+func (e *Emitter) Flush() error {
+	var lines []string
+	e.mu.Lock()
+	for name, h := range e.histograms {
+		lines = e.appendHistogram(lines, name, h.Drain())
+	}
+	for name, c := range e.counters {
+		if n := atomic.SwapInt64(&c.n, 0); n != 0 {
+			lines = append(lines, e.line(name, strconv.FormatInt(n, 10), "c"))
+		}
+	}
+	for name, ts := range e.tickers {
+		skipped := ts.t.Skipped()
+		if d := skipped - ts.skipped; d != 0 {
+			lines = append(lines, e.line(name+".skipped", strconv.FormatUint(d, 10), "c"))
+		}
+		ts.skipped = skipped
+	}
+	e.mu.Unlock()
+	return e.send(lines)
+}
+
+// Close stops the periodic flushes, flushes one last time and closes the
+// connection. Calling Close more than once has no further effect, and returns
+// the first call's error.
+func (e *Emitter) Close() error {
+	e.closeOnce.Do(func() {
+		close(e.quit)
+		<-e.exited
+		e.ticker.Stop()
+		e.closeErr = e.Flush()
+		if err := e.conn.Close(); e.closeErr == nil {
+			e.closeErr = err
+		}
+	})
+	return e.closeErr
+}
+
+func (e *Emitter) run() {
+	defer close(e.exited)
+	for {
+		select {
+		case <-e.ticker.C:
+		case <-e.quit:
+			return
+		}
+		if err := e.Flush(); err != nil && e.cfg.OnError != nil {
+			e.cfg.OnError(err)
+		}
+	}
+}
+
+func (e *Emitter) appendHistogram(lines []string, name string, h *monotime.Histogram) []string {
+	n := h.Count()
+	if n == 0 {
+		return lines
+	}
+	lines = append(lines,
+		e.line(name+".count", strconv.FormatUint(n, 10), "c"),
+		e.line(name+".min", millis(h.Min()), "g"),
+		e.line(name+".max", millis(h.Max()), "g"),
+		e.line(name+".mean", millis(h.Mean()), "g"),
+	)
+	for _, q := range e.cfg.Quantiles {
+		lines = append(lines, e.line(name+".p"+quantileName(q), millis(h.Quantile(q)), "g"))
+	}
+	return lines
+}
+
+// line formats one metric in the StatsD line protocol.
+func (e *Emitter) line(name, value, typ string) string {
+	return e.cfg.Prefix + name + ":" + value + "|" + typ + e.tags
+}
+
+// send writes lines to the agent, as few to a datagram as fit.
+func (e *Emitter) send(lines []string) error {
+	var buf bytes.Buffer
+	for _, l := range lines {
+		if buf.Len() > 0 && buf.Len()+1+len(l) > maxPacket {
+			if _, err := e.conn.Write(buf.Bytes()); err != nil {
+				return fmt.Errorf("Error sending to statsd agent: %w", err)
+			}
+			buf.Reset()
+		}
+		if buf.Len() > 0 {
+			buf.WriteByte('\n')
+		}
+		buf.WriteString(l)
+	}
+	if buf.Len() > 0 {
+		if _, err := e.conn.Write(buf.Bytes()); err != nil {
+			return fmt.Errorf("Error sending to statsd agent: %w", err)
+		}
+	}
+	return nil
+}
+
+// millis formats d in milliseconds, the unit StatsD expects for timings.
+func millis(d time.Duration) string {
+	return strconv.FormatFloat(float64(d)/float64(time.Millisecond), 'f', -1, 64)
+}
+
+// quantileName names quantile q for a metric suffix: 0.99 is "99", 0.999
+// "99_9".
+func quantileName(q float64) string {
+	// Round away float noise: 0.29*100 is 28.999999999999996.
+	s := strconv.FormatFloat(math.Round(q*1e6)/1e4, 'f', -1, 64)
+	return strings.Replace(s, ".", "_", 1)
+}
@@ -0,0 +1,120 @@
+package statsd
+
+import (
+	"net"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/thisguycodes/monotime"
+)
+
+// listen returns a UDP listener for the emitter to send to.
+func listen(t *testing.T) *net.UDPConn {
+	t.Helper()
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+// receive reads one datagram and returns its lines, sorted.
+func receive(t *testing.T, conn *net.UDPConn) []string {
+	t.Helper()
+	buf := make([]byte, maxPacket)
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	n, err := conn.Read(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(string(buf[:n]), "\n")
+	sort.Strings(lines)
+	return lines
+}
+
+func TestEmitterFlush(t *testing.T) {
+	conn := listen(t)
+	e, err := New(Config{Addr: conn.LocalAddr().String(), Prefix: "app.", Tags: []string{"env:test"}, Interval: time.Hour, Quantiles: []float64{0.5}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer e.Close()
+
+	e.Counter("requests").Add(3)
+	h := monotime.NewHistogram()
+	h.Record(2 * time.Millisecond)
+	e.Histogram("latency", h)
+	if err := e.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	got := receive(t, conn)
+	want := []string{
+		"app.latency.count:1|c|#env:test",
+		"app.latency.max:",
+		"app.latency.mean:",
+		"app.latency.min:",
+		"app.latency.p50:",
+		"app.requests:3|c|#env:test",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got lines %q", got)
+	}
+	for i := range want {
+		if !strings.HasPrefix(got[i], want[i]) {
+			t.Errorf("line %d = %q, want prefix %q", i, got[i], want[i])
+		}
+	}
+	if h.Count() != 0 {
+		t.Error("flush did not drain the histogram")
+	}
+}
+
+func TestEmitterCloseTwice(t *testing.T) {
+	conn := listen(t)
+	e, err := New(Config{Addr: conn.LocalAddr().String()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := e.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNewRejects(t *testing.T) {
+	for _, cfg := range []Config{
+		{Interval: -time.Second},
+		{Quantiles: []float64{1.5}},
+	} {
+		if e, err := New(cfg); err == nil {
+			e.Close()
+			t.Errorf("New(%+v) succeeded", cfg)
+		}
+	}
+}
+
+func TestQuantileName(t *testing.T) {
+	for q, want := range map[float64]string{
+		0.5:   "50",
+		0.29:  "29",
+		0.99:  "99",
+		0.999: "99_9",
+		1:     "100",
+	} {
+		if got := quantileName(q); got != want {
+			t.Errorf("quantileName(%v) = %q, want %q", q, got, want)
+		}
+	}
+}
+
+func TestMillis(t *testing.T) {
+	if got := millis(1500 * time.Microsecond); got != "1.5" {
+		t.Errorf("millis(1.5ms) = %q", got)
+	}
+}
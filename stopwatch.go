@@ -0,0 +1,77 @@
+package monotime
+
+import "time"
+
+// Stopwatch accumulates elapsed time on a clock across any number of start
+// and stop intervals. By default the clock is the monotonic clock, but any
+// source of increasing readings will do, such as a CPU time clock. A
+// Stopwatch is not safe for concurrent use.
+type Stopwatch struct {
+	now     func() Time
+	start   Time
+	lap     Time
+	elapsed time.Duration
+	running bool
+}
+
+// StartStopwatch returns a running Stopwatch on the monotonic clock.
+func StartStopwatch() *Stopwatch {
+	return StartStopwatchOn(Now)
+}
+
+// StartStopwatchOn returns a running Stopwatch on the clock now reads.
+func StartStopwatchOn(now func() Time) *Stopwatch {
+	s := &Stopwatch{now: now}
+	s.Start()
+	return s
+}
+
+// Start resumes a stopped Stopwatch. It has no effect if s is running.
+func (s *Stopwatch) Start() {
+	if s.running {
+		return
+	}
+	s.start = s.now()
+	s.lap = s.start
+	s.running = true
+}
+
+// Stop pauses the Stopwatch and returns the total elapsed time. It has no
+// effect but the return if s is already stopped.
+func (s *Stopwatch) Stop() time.Duration {
+	if s.running {
+		s.elapsed += s.now().Sub(s.start)
+		s.running = false
+	}
+	return s.elapsed
+}
+
+// Elapsed returns the total time the Stopwatch has been running.
+func (s *Stopwatch) Elapsed() time.Duration {
+	if !s.running {
+		return s.elapsed
+	}
+	return s.elapsed + s.now().Sub(s.start)
+}
+
+// Lap returns the running time since the previous Lap, or since the
+// Stopwatch was last started, and begins a new lap. It returns zero if s is
+// stopped.
+func (s *Stopwatch) Lap() time.Duration {
+	if !s.running {
+		return 0
+	}
+	now := s.now()
+	d := now.Sub(s.lap)
+	s.lap = now
+	return d
+}
+
+// Reset zeroes the elapsed time, leaving the Stopwatch running or stopped.
+func (s *Stopwatch) Reset() {
+	s.elapsed = 0
+	if s.running {
+		s.start = s.now()
+		s.lap = s.start
+	}
+}
@@ -0,0 +1,42 @@
+package monotime
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStopwatch(t *testing.T) {
+	now := Time(0)
+	clock := func() Time { return now }
+	s := StartStopwatchOn(clock)
+
+	now = 10
+	if got := s.Lap(); got != 10 {
+		t.Errorf("Lap() = %v, want 10ns", got)
+	}
+	now = 25
+	if got := s.Stop(); got != 25 {
+		t.Errorf("Stop() = %v, want 25ns", got)
+	}
+	now = 100
+	if got := s.Elapsed(); got != 25 {
+		t.Errorf("Elapsed() while stopped = %v, want 25ns", got)
+	}
+	if got := s.Lap(); got != 0 {
+		t.Errorf("Lap() while stopped = %v, want 0", got)
+	}
+	s.Start()
+	now = 105
+	if got := s.Elapsed(); got != 30 {
+		t.Errorf("Elapsed() after restart = %v, want 30ns", got)
+	}
+	s.Reset()
+	now = 107
+	if got := s.Elapsed(); got != 2 {
+		t.Errorf("Elapsed() after Reset = %v, want 2ns", got)
+	}
+
+	if d := StartStopwatch().Elapsed(); d < 0 || d > time.Second {
+		t.Errorf("monotonic Stopwatch elapsed %v", d)
+	}
+}
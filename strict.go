@@ -0,0 +1,82 @@
+package monotime
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+)
+
+// RegressionError describes a Now reading that went backwards. It is the
+// value strict mode panics with when no handler is installed.
+type RegressionError struct {
+	// Prev is the latest reading observed before the regression.
+	Prev Time
+	// Now is the reading that was earlier than Prev.
+	Now Time
+}
+
+func (e *RegressionError) Error() string {
+	return fmt.Sprintf("monotonic clock went backwards by %s (from %v to %v)", e.Prev.Sub(e.Now), e.Prev, e.Now)
+}
+
+var (
+	strict int32
+
+	strictMu      sync.Mutex
+	strictLast    Time
+	strictHandler func(prev, now Time)
+)
+
+func init() {
+	if os.Getenv("MONOTIME_STRICT") == "1" {
+		EnableStrict(nil)
+	}
+}
+
+// EnableStrict turns on strict-monotonicity checking: every result of Now is
+// compared against the latest reading observed by any goroutine, and if it is
+// earlier, handler is called with both readings. A nil handler panics with a
+// *RegressionError instead. Now still returns the regressed reading.
+//
+// Strict mode is a debugging aid for catching broken hypervisor clocks and
+// backend bugs. It serializes every call to Now, so leave it off in
+// production. Setting MONOTIME_STRICT=1 in the environment enables it with a
+// nil handler at startup.
+func EnableStrict(handler func(prev, now Time)) {
+	strictMu.Lock()
+	strictHandler = handler
+	strictLast = now()
+	strictMu.Unlock()
+	atomic.StoreInt32(&strict, 1)
+}
+
+// DisableStrict turns strict-monotonicity checking off.
+func DisableStrict() {
+	atomic.StoreInt32(&strict, 0)
+}
+
+func strictNow() (Time, error) {
+	// The clock is read under the lock so that readings are checked in
+	// the order they were taken; racing readers would otherwise report
+	// regressions that never happened.
+	strictMu.Lock()
+	t, err := nowErr()
+	if err != nil {
+		strictMu.Unlock()
+		return 0, err
+	}
+	prev, handler := strictLast, strictHandler
+	if !t.Before(prev) {
+		strictLast = t
+		strictMu.Unlock()
+		return t, nil
+	}
+	strictMu.Unlock()
+
+	if handler == nil {
+		panic(&RegressionError{Prev: prev, Now: t})
+	}
+	handler(prev, t)
+	return t, nil
+}
@@ -0,0 +1,119 @@
+//go:build darwin || linux || openbsd || windows
+// +build darwin linux openbsd windows
+
+package monotime
+
+import (
+	"sync"
+	"time"
+)
+
+// suspendThreshold is how much the gap between the boottime and monotonic
+// clocks must grow for a SuspendMonitor to report a suspend. Smaller changes
+// are the noise of reading two clocks one after the other.
+const suspendThreshold = 10 * time.Millisecond
+
+// Suspend describes a period the system spent suspended, as reported by a
+// SuspendMonitor.
+type Suspend struct {
+	// Duration is how long the system was suspended: how much the
+	// boottime clock gained on the monotonic clock.
+	Duration time.Duration
+	// Detected is when the monitor noticed the system had resumed, on the
+	// monotonic clock.
+	Detected Time
+}
+
+// SuspendMonitor reports each time the system has been suspended, so that
+// logic such as lease renewal can tell the machine slept rather than merely
+// fell behind. It compares the clock NowBoottime reads, which keeps counting
+// through a suspend, with Now, which doesn't, on a ticker on the boottime
+// clock that fires promptly on resume.
+type SuspendMonitor struct {
+	// C receives a Suspend after each resume. It holds one value; suspends
+	// that come before it is received are added to it.
+	C <-chan Suspend
+
+	c      chan Suspend
+	ticker *Ticker
+	quit   chan struct{}
+	exited chan struct{}
+	copy   copyCheck
+
+	stopOnce sync.Once
+}
+
+// NewSuspendMonitor returns a SuspendMonitor that compares the clocks every
+// interval, which bounds how long after a resume the suspend is reported.
+// interval must be greater than zero; if not, NewSuspendMonitor will panic.
+// It returns an error if the kernel can't provide the boottime timer. Stop
+// the monitor to release it.
+func NewSuspendMonitor(interval time.Duration) (*SuspendMonitor, error) {
+	if interval <= 0 {
+		panic("non-positive interval for NewSuspendMonitor")
+	}
+	// A tick after the resume says all there is to say; the ones missed
+	// while suspended add nothing.
+	ticker, err := NewTickerErr(interval, WithClock(ClockBoottime), WithMissedTickPolicy(Coalesce))
+	if err != nil {
+		return nil, err
+	}
+	c := make(chan Suspend, 1)
+	m := &SuspendMonitor{C: c, c: c, ticker: ticker, quit: make(chan struct{}), exited: make(chan struct{})}
+	m.copy.init()
+	go m.run(suspendedSoFar())
+	return m, nil
+}
+
+// suspendedSoFar returns how far the boottime clock is ahead of the monotonic
+// clock, reading it between two monotonic readings and taking their midpoint.
+func suspendedSoFar() time.Duration {
+	before := now()
+	boot := NowBoottime()
+	after := now()
+	return boot.Sub(before.Add(after.Sub(before) / 2))
+}
+
+func (m *SuspendMonitor) run(offset time.Duration) {
+	defer close(m.exited)
+	for {
+		select {
+		case <-m.ticker.C:
+		case <-m.quit:
+			return
+		}
+		cur := suspendedSoFar()
+		if slept := cur - offset; slept >= suspendThreshold {
+			m.send(Suspend{Duration: slept, Detected: now()})
+		}
+		// Following the gap down as well as up keeps noise in one
+		// reading from hiding the next suspend, or inventing one.
+		offset = cur
+	}
+}
+
+// send delivers s, adding it to an unread Suspend. Only the monitor's
+// goroutine sends, so after taking the unread one the next send succeeds.
+func (m *SuspendMonitor) send(s Suspend) {
+	for {
+		select {
+		case m.c <- s:
+			return
+		default:
+		}
+		select {
+		case old := <-m.c:
+			s.Duration += old.Duration
+		default:
+		}
+	}
+}
+
+// Stop turns off the monitor. Once Stop returns no more suspends are
+// reported. Calling Stop more than once has no further effect.
+func (m *SuspendMonitor) Stop() {
+	m.copy.check("SuspendMonitor.Stop")
+	m.stopOnce.Do(func() { close(m.quit) })
+	<-m.exited
+	m.ticker.Stop()
+}
@@ -0,0 +1,64 @@
+//go:build darwin || linux || openbsd || windows
+// +build darwin linux openbsd windows
+
+package monotime
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSuspendMonitor(t *testing.T) {
+	m, err := NewSuspendMonitor(time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Nothing suspends the test machine, so nothing should be reported.
+	select {
+	case s := <-m.C:
+		t.Errorf("reported a suspend of %v without one", s.Duration)
+	case <-time.After(50 * time.Millisecond):
+	}
+	m.Stop()
+	m.Stop()
+	select {
+	case <-m.ticker.C:
+		t.Error("ticker still running after Stop")
+	case <-time.After(10 * time.Millisecond):
+	}
+}
+
+func TestSuspendMonitorConcurrentStop(t *testing.T) {
+	m, err := NewSuspendMonitor(time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			m.Stop()
+		}()
+	}
+	wg.Wait()
+}
+
+func TestSuspendMonitorMerges(t *testing.T) {
+	c := make(chan Suspend, 1)
+	m := &SuspendMonitor{C: c, c: c}
+	m.send(Suspend{Duration: time.Second})
+	m.send(Suspend{Duration: 2 * time.Second, Detected: 7})
+	s := <-m.C
+	if s.Duration != 3*time.Second || s.Detected != 7 {
+		t.Errorf("got %+v, want 3s detected at 7", s)
+	}
+}
+
+func TestSuspendedSoFar(t *testing.T) {
+	first := suspendedSoFar()
+	if d := suspendedSoFar() - first; d < -suspendThreshold || d >= suspendThreshold {
+		t.Errorf("gap between clocks moved %v without a suspend", d)
+	}
+}
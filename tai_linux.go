@@ -0,0 +1,73 @@
+package monotime
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// TAITime is a reading of CLOCK_TAI: nanoseconds of International Atomic Time
+// since 1970-01-01 00:00:00 TAI. Unlike UTC it has no leap seconds, which
+// makes it the time scale of PTP and of systems disciplined by it. It runs
+// ahead of the wall clock by the TAI-UTC offset, 37 seconds since 2017.
+type TAITime int64
+
+// NowTAI returns the current time on CLOCK_TAI. The kernel only knows the
+// TAI-UTC offset if a time daemon has told it, as chrony and ptp4l do;
+// otherwise CLOCK_TAI equals CLOCK_REALTIME, and TAIOffset reports an error.
+func NowTAI() TAITime {
+	return TAITime(readClock(unix.CLOCK_TAI))
+}
+
+// TAIOffset returns the kernel's current TAI-UTC offset.
+func TAIOffset() (time.Duration, error) {
+	var tx unix.Timex
+	if _, err := unix.Adjtimex(&tx); err != nil {
+		return 0, fmt.Errorf("Error reading TAI offset from the kernel: %w", err)
+	}
+	if tx.Tai == 0 {
+		return 0, errors.New("Error reading TAI offset from the kernel: offset not set by a time daemon")
+	}
+	return time.Duration(tx.Tai) * time.Second, nil
+}
+
+// UTC returns t as a wall clock time, given the TAI-UTC offset in effect at t,
+// as from TAIOffset.
+func (t TAITime) UTC(offset time.Duration) time.Time {
+	return time.Unix(0, int64(t)-int64(offset)).UTC()
+}
+
+// TAIFromTime returns the TAI time of the wall clock time w, given the TAI-UTC
+// offset in effect at w.
+func TAIFromTime(w time.Time, offset time.Duration) TAITime {
+	return TAITime(w.UnixNano() + int64(offset))
+}
+
+// Sub returns the duration t-u.
+func (t TAITime) Sub(u TAITime) time.Duration {
+	return time.Duration(t - u)
+}
+
+// FromTAI converts a CLOCK_TAI timestamp, such as a PTP hardware timestamp on
+// the TAI time scale, into a Time. The two clocks are slewed together by the
+// time daemon, but CLOCK_TAI steps with the wall clock, so the conversion is
+// exact only for timestamps since the last step.
+func FromTAI(t TAITime) Time {
+	return Time(int64(t) - int64(clockOffset(unix.CLOCK_TAI)))
+}
+
+// ToTAI converts a Time into a CLOCK_TAI timestamp, as FromTAI's inverse.
+func ToTAI(t Time) TAITime {
+	return TAITime(int64(t) + int64(clockOffset(unix.CLOCK_TAI)))
+}
+
+// clockOffset returns how far the given clock is ahead of CLOCK_MONOTONIC,
+// reading it between two monotonic readings and taking their midpoint.
+func clockOffset(clockid int32) time.Duration {
+	before := now()
+	c := readClock(clockid)
+	after := now()
+	return c.Sub(before.Add(after.Sub(before) / 2))
+}
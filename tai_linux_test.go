@@ -0,0 +1,34 @@
+package monotime
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNowTAI(t *testing.T) {
+	// CLOCK_TAI is the wall clock plus the TAI-UTC offset, or plus nothing
+	// if no time daemon set it.
+	d := NowTAI().UTC(0).Sub(time.Now())
+	if d < -time.Second || d > 40*time.Second {
+		t.Errorf("CLOCK_TAI is %v ahead of the wall clock", d)
+	}
+	if off, err := TAIOffset(); err == nil && (off < 10*time.Second || off > 60*time.Second) {
+		t.Errorf("TAIOffset() = %v", off)
+	}
+}
+
+func TestTAIConversions(t *testing.T) {
+	w := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	tai := TAIFromTime(w, 37*time.Second)
+	if got := tai.UTC(37 * time.Second); !got.Equal(w) {
+		t.Errorf("UTC round trip gives %v, want %v", got, w)
+	}
+	if got := tai.Sub(TAIFromTime(w, 0)); got != 37*time.Second {
+		t.Errorf("TAI ahead of UTC by %v", got)
+	}
+
+	now := Now()
+	if d := FromTAI(ToTAI(now)).Sub(now); d < -time.Millisecond || d > time.Millisecond {
+		t.Errorf("monotonic round trip off by %v", d)
+	}
+}
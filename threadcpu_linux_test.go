@@ -0,0 +1,38 @@
+package monotime
+
+import (
+	"testing"
+	"time"
+)
+
+func TestThreadCPUMeter(t *testing.T) {
+	m := StartThreadCPU()
+	time.Sleep(20 * time.Millisecond)
+	idle := m.Checkpoint()
+
+	start := Now()
+	for Now().Sub(start) < 30*time.Millisecond {
+	}
+	busy := m.Checkpoint()
+
+	if idle > 10*time.Millisecond {
+		t.Errorf("sleeping 20ms used %v of thread CPU time", idle)
+	}
+	if busy < 5*time.Millisecond || busy > 35*time.Millisecond {
+		t.Errorf("spinning 30ms used %v of thread CPU time", busy)
+	}
+
+	total := m.Stop()
+	if total < idle+busy {
+		t.Errorf("Stop() = %v, less than the checkpoints' %v", total, idle+busy)
+	}
+	if d := m.Checkpoint(); d != 0 {
+		t.Errorf("Checkpoint after Stop = %v, want 0", d)
+	}
+	if got := m.Total(); got != total {
+		t.Errorf("Total after Stop = %v, want %v", got, total)
+	}
+	if got := m.Stop(); got != total {
+		t.Errorf("second Stop = %v, want %v", got, total)
+	}
+}
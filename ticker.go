@@ -0,0 +1,485 @@
+package monotime
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Ticker holds a channel that delivers ticks at intervals, driven by a kernel
+// timer on the monotonic clock. Unlike time.Ticker, it is unaffected by
+// changes to the wall clock.
+//
+// A Ticker dropped without being stopped is stopped once the garbage
+// collector finds it unreachable, releasing its kernel timer and goroutine.
+// Collection may come late or not at all, so stop tickers when done with them
+// all the same.
+type Ticker struct {
+	// C receives one value per expiration of the timer: the time the tick
+	// came due, on the clock the ticker runs on. A tick received late still
+	// carries its due time, so the receiver can tell how far behind it is.
+	C <-chan Time
+
+	// The kernel timer's goroutine holds only the tickerState, so that
+	// the Ticker itself can become unreachable while it runs.
+	*tickerState
+	copy copyCheck
+}
+
+// tickerState is the part of a Ticker its kernel timer's callback uses.
+type tickerState struct {
+	// Accessed atomically; first so it is 64-bit aligned on 32-bit
+	// platforms.
+	skipped uint64
+	sending uint32 // set while a tick waits for the receiver
+
+	c       chan Time
+	lagging chan Lag
+	ack     chan struct{} // signalled by Ack, for fixed-delay tickers
+	now     func() Time   // reads the clock the ticker runs on
+	kt      *kernelTimer
+
+	d            time.Duration
+	interval     time.Duration // the kernel timer's; zero if armed per tick
+	maxCatchUp   uint64
+	lagThreshold time.Duration
+	policy       MissedTickPolicy
+	slack        time.Duration
+
+	mu       sync.Mutex
+	pausec   chan struct{} // closed by Pause, replaced by Resume
+	resumeAt Time          // when the first tick since Resume is due
+}
+
+// MissedTickPolicy is how a Ticker delivers ticks that came due while its
+// receiver was busy; see WithMissedTickPolicy.
+type MissedTickPolicy int32
+
+const (
+	// DeliverAll delivers every missed tick, back to back, as soon as the
+	// receiver is ready, up to the WithMaxCatchUp limit.
+	DeliverAll MissedTickPolicy = iota
+	// Coalesce delivers the ticks missed since the last one received as a
+	// single tick, carrying the latest due time.
+	Coalesce
+	// Resync drops every tick the receiver is not ready for when it comes
+	// due, so that the next tick received is the first due after the
+	// receiver is ready again, as with time.Ticker.
+	Resync
+)
+
+func (p MissedTickPolicy) String() string {
+	switch p {
+	case DeliverAll:
+		return "deliver-all"
+	case Coalesce:
+		return "coalesce"
+	case Resync:
+		return "resync"
+	}
+	return "unknown"
+}
+
+// Lag describes a Ticker falling behind its schedule; see
+// WithLagThreshold.
+type Lag struct {
+	// Delay is how long after its due time the latest tick was received.
+	Delay time.Duration
+	// Backlog is the number of ticks that had come due but were still
+	// waiting to be received.
+	Backlog uint64
+}
+
+// NewTicker returns a new Ticker whose first tick is d from now, and every d
+// after that, configured by opts. d must be greater than zero; if not,
+// NewTicker will panic. It also panics if the kernel can't provide a timer;
+// see NewTickerErr. Stop the ticker to release its kernel resources.
+func NewTicker(d time.Duration, opts ...TickerOption) *Ticker {
+	ticker, err := NewTickerErr(d, opts...)
+	if err != nil {
+		panic(err)
+	}
+	return ticker
+}
+
+// NewTickerErr is like NewTicker, but returns an error instead of panicking if
+// the ticker can't be created, as when the process is out of file descriptors
+// or an option asks for something the host lacks. It still panics if d is not
+// greater than zero.
+func NewTickerErr(d time.Duration, opts ...TickerOption) (*Ticker, error) {
+	if d <= 0 {
+		panic("non-positive interval for NewTicker")
+	}
+	cfg := tickerConfig{clock: ClockMonotonic}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	c, ok := timerClocks[cfg.clock]
+	if !ok {
+		return nil, fmt.Errorf("Error creating ticker: no timers on the %v clock on %s", cfg.clock, runtime.GOOS)
+	}
+	if cfg.newTimer != nil {
+		clock := cfg.clock
+		c.newTimer = func() (*kernelTimer, error) { return cfg.newTimer(clock) }
+	}
+	start := c.now().Add(d)
+	if cfg.start.IsSet() {
+		start = cfg.start
+	}
+
+	interval := d
+	if cfg.mode != periodic {
+		// The other modes arm a one-shot timer for each tick.
+		interval = 0
+	}
+	ticker, err := armTicker(c, roundUp(start, cfg.slack), interval, cfg.buffer)
+	if err != nil {
+		return nil, err
+	}
+	ticker.slack = cfg.slack
+	ticker.d = d
+	ticker.maxCatchUp = cfg.maxCatchUp
+	ticker.lagThreshold = cfg.lagThreshold
+	ticker.policy = cfg.policy
+
+	fire := ticker.fire(start, d)
+	switch cfg.mode {
+	case fixedRate:
+		fire = ticker.fireFixedRate(start, d)
+	case fixedDelay:
+		ticker.ack = make(chan struct{}, 1)
+		fire = ticker.fireFixedDelay(start, d)
+	}
+	if cfg.launch == nil {
+		ticker.kt.start(fire)
+	} else if err := cfg.launch(ticker.kt, fire); err != nil {
+		ticker.kt.release()
+		return nil, err
+	}
+	runtime.SetFinalizer(ticker, func(t *Ticker) { t.stop() })
+	return ticker, nil
+}
+
+// NewTickerContext is like NewTicker, but the ticker is stopped, and its
+// kernel resources released, once ctx is done, so a ticker scoped to a
+// request can't outlive it. Ticks stop then as if Stop had been called;
+// receivers should select on ctx.Done() as well as C. Stopping the ticker
+// before ctx is done is still allowed and ends the wait on ctx.
+func NewTickerContext(ctx context.Context, d time.Duration, opts ...TickerOption) *Ticker {
+	ticker := NewTicker(d, opts...)
+	s := ticker.tickerState
+	go func() {
+		select {
+		case <-ctx.Done():
+			s.stop()
+		case <-s.kt.done:
+		}
+	}()
+	return ticker
+}
+
+// armTicker returns a Ticker on clock c whose kernel timer is armed to expire
+// at t and every interval after, zero for one-shot, and is yet to be started.
+// Its channel has room for buffer ticks.
+func armTicker(c kernelClock, t Time, interval time.Duration, buffer int) (*Ticker, error) {
+	kt, err := c.newTimer()
+	if err != nil {
+		return nil, err
+	}
+	if err := kt.arm(t, interval); err != nil {
+		kt.release()
+		return nil, err
+	}
+
+	ch := make(chan Time, buffer)
+	ticker := &Ticker{C: ch, tickerState: &tickerState{
+		c:        ch,
+		lagging:  make(chan Lag, 1),
+		now:      c.now,
+		kt:       kt,
+		interval: interval,
+		pausec:   make(chan struct{}),
+	}}
+	ticker.copy.init()
+	return ticker, nil
+}
+
+// fire returns the kernel timer callback for a ticker whose first tick is due at
+// due and every d after.
+func (t *tickerState) fire(due Time, d time.Duration) func(n uint64) bool {
+	pause := t.pausec
+	return func(n uint64) bool {
+		if t.paused(&pause, &due) {
+			return true
+		}
+		switch t.policy {
+		case Coalesce:
+			return t.deliverLatest(&due, d, n, false, pause)
+		case Resync:
+			return t.deliverLatest(&due, d, n, true, pause)
+		}
+		if max := t.maxCatchUp; max > 0 && n > max {
+			atomic.AddUint64(&t.skipped, n-max)
+			due = due.AddSat(mulSat(int64(n-max), d))
+			n = max
+		}
+		for i := uint64(0); i < n; i++ {
+			if sent, ok := t.send(due, pause); !ok {
+				return false
+			} else if !sent {
+				return true
+			}
+			t.checkLag(due, n-1-i)
+			due = due.Add(d)
+		}
+		return true
+	}
+}
+
+// deliverLatest sends only the latest of n expirations, the first due at
+// *due, and counts the rest as skipped. If drop is set it is dropped too,
+// unless the receiver is ready for it. It returns false if the ticker was
+// stopped, and gives up once pause is closed.
+func (t *tickerState) deliverLatest(due *Time, d time.Duration, n uint64, drop bool, pause <-chan struct{}) bool {
+	latest := due.Add(time.Duration(n-1) * d)
+	*due = latest.Add(d)
+	atomic.AddUint64(&t.skipped, n-1)
+	if drop {
+		select {
+		case t.c <- latest:
+		default:
+			atomic.AddUint64(&t.skipped, 1)
+			return true
+		}
+	} else if sent, ok := t.send(latest, pause); !ok {
+		return false
+	} else if !sent {
+		return true
+	}
+	t.checkLag(latest, 0)
+	return true
+}
+
+// send hands the receiver a tick due at due, reporting whether it did. If not,
+// ok is false if the ticker was stopped, leaving the tick pending for Stop to
+// report, and true if it was paused.
+func (t *tickerState) send(due Time, pause <-chan struct{}) (sent, ok bool) {
+	atomic.StoreUint32(&t.sending, 1)
+	select {
+	case t.c <- due:
+		atomic.StoreUint32(&t.sending, 0)
+		return true, true
+	case <-pause:
+		atomic.StoreUint32(&t.sending, 0)
+		return false, true
+	case <-t.kt.done:
+		return false, false
+	}
+}
+
+// paused reports whether the ticker is paused, for a kernel timer callback
+// that last saw the pause channel *pause. If the ticker has been resumed
+// since, it moves *pause to the current channel and *due to the first tick
+// due after Resume.
+func (t *tickerState) paused(pause *chan struct{}, due *Time) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if *pause != t.pausec {
+		*pause, *due = t.pausec, t.resumeAt
+	}
+	select {
+	case <-*pause:
+		return true
+	default:
+		return false
+	}
+}
+
+// rearm arms the one-shot kernel timer of a ticker for a tick due at due, as
+// late as its slack allows, unless it has been paused since pause was
+// current. It returns false if the timer failed.
+func (t *tickerState) rearm(pause <-chan struct{}, due Time) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	select {
+	case <-pause:
+		return true
+	default:
+	}
+	if err := t.kt.arm(roundUp(due, t.slack), 0); err != nil {
+		t.kt.fail(err)
+		return false
+	}
+	return true
+}
+
+// NextTick returns when the ticker's kernel timer next expires, on the
+// ticker's clock, so a scheduler can show when the next run is due or fit
+// other work around it. Ticks that came due but have not been received don't
+// count; it is the expiration after them. NextTick reports false if the timer
+// is not armed: while the ticker is paused or stopped, or a fixed-delay ticker
+// waits for Ack. It panics if the kernel timer can't be read.
+func (t *Ticker) NextTick() (Time, bool) {
+	select {
+	case <-t.kt.done:
+		return 0, false
+	default:
+	}
+	left, armed, err := t.kt.remaining()
+	if err != nil {
+		panic(err)
+	}
+	if !armed {
+		return 0, false
+	}
+	return t.now().Add(left), true
+}
+
+// Period returns the interval the ticker was created with: the time between
+// ticks, or for a fixed-delay ticker between each Ack and the next tick.
+func (t *Ticker) Period() time.Duration {
+	return t.d
+}
+
+// Interval returns the time left until NextTick, and false when NextTick
+// does. It is negative if the kernel timer is overdue.
+func (t *Ticker) Interval() (time.Duration, bool) {
+	next, ok := t.NextTick()
+	if !ok {
+		return 0, false
+	}
+	return next.Sub(t.now()), true
+}
+
+// Pause holds back the ticker's ticks until Resume. Its kernel timer is
+// disarmed but kept, along with its goroutine, so a ticker paused and resumed
+// costs less than one stopped and created anew. Ticks not yet sent are
+// dropped, but one already sent, or held by WithBuffer, may still be
+// received. Pause has no effect on a ticker that is paused or stopped. It
+// panics if the kernel timer can't be disarmed.
+func (t *Ticker) Pause() {
+	t.copy.check("Ticker.Pause")
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	select {
+	case <-t.pausec:
+		return
+	case <-t.kt.exited:
+		return
+	default:
+	}
+	close(t.pausec)
+	if err := t.kt.disarm(); err != nil {
+		panic(err)
+	}
+}
+
+// Resume restarts a paused ticker, with its next tick due d from now and every
+// d after, d being the interval it was created with. A fixed-delay ticker
+// does not wait for an Ack of a tick from before Pause. Resume has no effect
+// on a ticker that is not paused, or is stopped. It panics if the kernel timer
+// can't be armed.
+func (t *Ticker) Resume() {
+	t.copy.check("Ticker.Resume")
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	select {
+	case <-t.kt.exited:
+		return
+	case <-t.pausec:
+	default:
+		return
+	}
+	t.resumeAt = t.now().Add(t.d)
+	t.pausec = make(chan struct{})
+	select {
+	case <-t.ack:
+	default:
+	}
+	if err := t.kt.arm(roundUp(t.resumeAt, t.slack), t.interval); err != nil {
+		panic(err)
+	}
+}
+
+// Skipped returns the number of ticks dropped because of the WithMaxCatchUp
+// limit or the missed-tick policy.
+func (t *Ticker) Skipped() uint64 {
+	return atomic.LoadUint64(&t.skipped)
+}
+
+// Lagging returns the channel on which the ticker reports falling behind, once
+// a threshold is set with WithLagThreshold. It holds only the latest report;
+// older unread ones are replaced.
+func (t *Ticker) Lagging() <-chan Lag {
+	return t.lagging
+}
+
+// Err returns a channel that receives the error that ended the ticker, should
+// its kernel timer fail. No more ticks are sent after a failure; the ticker
+// must still be stopped, but its kernel resources are already released. The
+// channel is never closed, and receives nothing if the ticker is stopped.
+func (t *Ticker) Err() <-chan error {
+	return t.kt.errc
+}
+
+// checkLag reports a tick due at due, received with backlog more ticks
+// waiting, if it was received too late.
+func (t *tickerState) checkLag(due Time, backlog uint64) {
+	threshold := t.lagThreshold
+	if threshold == 0 {
+		return
+	}
+	delay := t.now().Sub(due)
+	if delay <= threshold {
+		return
+	}
+	lag := Lag{Delay: delay, Backlog: backlog}
+	for {
+		select {
+		case t.lagging <- lag:
+			return
+		default:
+		}
+		// Only this goroutine sends, so after discarding the stale
+		// report the next send succeeds.
+		select {
+		case <-t.lagging:
+		default:
+		}
+	}
+}
+
+// Stop turns off the ticker. Once Stop returns, no more ticks will be sent,
+// the ticker's goroutine has exited and its kernel timer is released. Ticks
+// left in a WithBuffer channel are discarded. Stop reports whether any ticks
+// had come due without being received, and so were lost. It does not close
+// the channel, to prevent a concurrent goroutine reading from the channel
+// from seeing an erroneous "tick". Calling Stop more than once has no further
+// effect, and reports false.
+func (t *Ticker) Stop() bool {
+	t.copy.check("Ticker.Stop")
+	runtime.SetFinalizer(t, nil)
+	return t.stop()
+}
+
+// stop does the work of Stop, for callers holding only the tickerState: the
+// finalizer and NewTickerContext.
+func (t *tickerState) stop() bool {
+	t.kt.stop()
+	// The goroutine has exited, so nothing else touches sending.
+	pending := atomic.SwapUint32(&t.sending, 0) != 0 || len(t.c) > 0
+	for len(t.c) > 0 {
+		select {
+		case <-t.c:
+		default:
+		}
+	}
+	if checksEnabled() {
+		buf := make([]byte, 64<<10)
+		go watchStaleReads("Ticker.C", t.c, buf[:runtime.Stack(buf, false)])
+	}
+	return pending
+}
@@ -0,0 +1,38 @@
+package monotime
+
+import (
+	"errors"
+	"runtime"
+	"testing"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+func TestNewTickerErrOutOfFDs(t *testing.T) {
+	exhaustFDs(t)
+	ticker, err := NewTickerErr(time.Millisecond)
+	if !errors.Is(err, unix.EMFILE) {
+		t.Fatalf("NewTickerErr with no descriptors left: %v, %v; want EMFILE", ticker, err)
+	}
+}
+
+func TestTickerCollected(t *testing.T) {
+	// The shared poller's epoll instance, once created, stays open.
+	if _, err := sharedPollerErr(); err != nil {
+		t.Fatal(err)
+	}
+	before := openFDs(t)
+	func() {
+		NewTicker(time.Millisecond)
+		NewTicker(time.Millisecond, WithSharedPoller())
+	}()
+	deadline := time.Now().Add(5 * time.Second)
+	for openFDs(t) > before {
+		if time.Now().After(deadline) {
+			t.Fatalf("%d descriptors open after dropping two tickers, want %d", openFDs(t), before)
+		}
+		runtime.GC()
+		time.Sleep(time.Millisecond)
+	}
+}
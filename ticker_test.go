@@ -0,0 +1,378 @@
+package monotime
+
+import (
+	"context"
+	"math"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestTicker(t *testing.T) {
+	const d = 2 * time.Millisecond
+	start := Now()
+	ticker := NewTicker(d)
+	defer ticker.Stop()
+	for i := 1; i <= 5; i++ {
+		select {
+		case <-ticker.C:
+		case <-time.After(time.Second):
+			t.Fatalf("tick %d did not arrive", i)
+		}
+		if got := Now().Sub(start); got < time.Duration(i)*d {
+			t.Errorf("tick %d after %v, before it was due", i, got)
+		}
+	}
+}
+
+func TestTickerStop(t *testing.T) {
+	ticker := NewTicker(time.Millisecond)
+	<-ticker.C
+	ticker.Stop()
+	ticker.Stop()
+	select {
+	case <-ticker.C:
+		t.Fatal("tick after Stop")
+	case <-time.After(10 * time.Millisecond):
+	}
+}
+
+func TestTickerCatchUp(t *testing.T) {
+	ticker := NewTicker(time.Millisecond, WithStartAt(Now().Add(-10*time.Millisecond)), WithMaxCatchUp(1))
+	defer ticker.Stop()
+	// The first expiration read covers the ten missed ones; with a limit
+	// of one, all but one are dropped.
+	time.Sleep(5 * time.Millisecond)
+	<-ticker.C
+	if skipped := ticker.Skipped(); skipped == 0 {
+		t.Error("no ticks skipped past the catch-up limit")
+	}
+}
+
+func TestTickerCatchUpSaturates(t *testing.T) {
+	// Skipping an enormous backlog pins the next tick at the end of time
+	// rather than wrapping it into the past.
+	ts := &tickerState{c: make(chan Time, 1), kt: &kernelTimer{}, maxCatchUp: 1}
+	if !ts.fire(Now(), time.Hour)(math.MaxInt64) {
+		t.Fatal("fire gave up")
+	}
+	if due, want := <-ts.c, Time(math.MaxInt64); due != want {
+		t.Errorf("tick after skipping the backlog due at %v, want %v", due, want)
+	}
+}
+
+func TestTickerLagging(t *testing.T) {
+	ticker := NewTicker(time.Millisecond, WithLagThreshold(time.Millisecond))
+	defer ticker.Stop()
+	<-ticker.C
+	time.Sleep(10 * time.Millisecond)
+	<-ticker.C
+	select {
+	case lag := <-ticker.Lagging():
+		if lag.Delay <= time.Millisecond {
+			t.Errorf("lag report of %v, under the threshold", lag.Delay)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no lag report")
+	}
+}
+
+func TestNewTickerPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("NewTicker(0) did not panic")
+		}
+	}()
+	NewTicker(0)
+}
+
+func TestNewTickerErr(t *testing.T) {
+	ticker, err := NewTickerErr(time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ticker.Stop()
+	select {
+	case <-ticker.C:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no tick")
+	}
+}
+
+func TestTickerDueTimes(t *testing.T) {
+	const d = time.Millisecond
+	first := Now().Add(d)
+	ticker := NewTicker(d, WithStartAt(first))
+	defer ticker.Stop()
+	for i := 0; i < 5; i++ {
+		due := <-ticker.C
+		if want := first.Add(time.Duration(i) * d); due != want {
+			t.Errorf("tick %d due at %d, want %d", i, due, want)
+		}
+		if now := Now(); now < due {
+			t.Errorf("tick %d received %v before it was due", i, due.Sub(now))
+		}
+	}
+}
+
+func TestTickerStartAtZero(t *testing.T) {
+	const d = 50 * time.Millisecond
+	start := Now()
+	ticker := NewTicker(d, WithStartAt(0))
+	defer ticker.Stop()
+	if next, _ := ticker.NextTick(); next.Before(start.Add(d)) || next.After(Now().Add(d)) {
+		t.Errorf("first tick due %v after start, want %v", next.Sub(start), d)
+	}
+}
+
+func TestTickerCoalesce(t *testing.T) {
+	const d = time.Millisecond
+	ticker := NewTicker(d, WithMissedTickPolicy(Coalesce))
+	defer ticker.Stop()
+
+	first := <-ticker.C
+	time.Sleep(10 * d)
+	// The tick already waiting to be sent comes first, then one tick
+	// standing for all those missed since.
+	<-ticker.C
+	late := <-ticker.C
+	if late.Sub(first) < 8*d {
+		t.Errorf("coalesced tick due %v after the first, want the latest of the missed ticks", late.Sub(first))
+	}
+	if ticker.Skipped() < 5 {
+		t.Errorf("Skipped() = %d after coalescing 10 periods", ticker.Skipped())
+	}
+}
+
+func TestTickerResync(t *testing.T) {
+	const d = 5 * time.Millisecond
+	ticker := NewTicker(d, WithMissedTickPolicy(Resync))
+	defer ticker.Stop()
+
+	<-ticker.C
+	time.Sleep(6 * d)
+	recv := Now()
+	due := <-ticker.C
+	// Ticks that came due while the receiver slept were dropped rather
+	// than queued.
+	if due.Sub(recv) < -d {
+		t.Errorf("tick received after resync was due %v before the receive", recv.Sub(due))
+	}
+	if ticker.Skipped() < 3 {
+		t.Errorf("Skipped() = %d after missing 6 periods", ticker.Skipped())
+	}
+}
+
+func TestNewTickerUnknownClock(t *testing.T) {
+	if ticker, err := NewTickerErr(time.Millisecond, WithClock(ClockProcessCPU)); err == nil {
+		ticker.Stop()
+		t.Fatal("ticker on a clock without timers")
+	}
+}
+
+func TestTickerBuffer(t *testing.T) {
+	const d = time.Millisecond
+	ticker := NewTicker(d, WithBuffer(4))
+	defer ticker.Stop()
+
+	// While the receiver is busy, the goroutine fills the buffer and
+	// holds one more tick, leaving the rest pending in the kernel timer.
+	time.Sleep(20 * d)
+	if n := len(ticker.C); n != 4 {
+		t.Fatalf("%d ticks buffered, want 4", n)
+	}
+	prev := <-ticker.C
+	for i := 0; i < 8; i++ {
+		due := <-ticker.C
+		if due.Sub(prev) != d {
+			t.Fatalf("tick %d due %v after the previous, want %v", i, due.Sub(prev), d)
+		}
+		prev = due
+	}
+
+	ticker.Stop()
+	if n := len(ticker.C); n != 0 {
+		t.Errorf("%d ticks left buffered after Stop", n)
+	}
+}
+
+func TestTickerPause(t *testing.T) {
+	const d = time.Millisecond
+	for name, opts := range map[string][]TickerOption{
+		"periodic":    nil,
+		"fixed-rate":  {WithFixedRate()},
+		"fixed-delay": {WithFixedDelay()},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ticker := NewTicker(d, opts...)
+			defer ticker.Stop()
+			<-ticker.C
+			ticker.Ack()
+
+			ticker.Pause()
+			ticker.Pause()
+			// A tick already on its way may still arrive.
+			select {
+			case <-ticker.C:
+			case <-time.After(5 * d):
+			}
+			select {
+			case <-ticker.C:
+				t.Fatal("tick while paused")
+			case <-time.After(20 * d):
+			}
+
+			resumed := Now()
+			ticker.Resume()
+			ticker.Resume()
+			for i := 0; i < 3; i++ {
+				select {
+				case due := <-ticker.C:
+					if due < resumed.Add(d) {
+						t.Fatalf("tick due %v before Resume became due", resumed.Add(d).Sub(due))
+					}
+				case <-time.After(time.Second):
+					t.Fatalf("tick %d after Resume did not arrive", i)
+				}
+				ticker.Ack()
+			}
+		})
+	}
+}
+
+func TestTickerPauseStopped(t *testing.T) {
+	ticker := NewTicker(time.Millisecond)
+	ticker.Stop()
+	ticker.Pause()
+	ticker.Resume()
+}
+
+func TestTickerNextTick(t *testing.T) {
+	const d = 50 * time.Millisecond
+	start := Now()
+	ticker := NewTicker(d)
+	defer ticker.Stop()
+	next, ok := ticker.NextTick()
+	if !ok {
+		t.Fatal("no next tick for a running ticker")
+	}
+	if next <= start || next > Now().Add(d) {
+		t.Errorf("next tick %v after start, want within %v", next.Sub(start), d)
+	}
+	if due := <-ticker.C; due.Sub(next) < -time.Millisecond || due.Sub(next) > time.Millisecond {
+		t.Errorf("tick due %v from the reported next tick", due.Sub(next))
+	}
+
+	ticker.Pause()
+	if _, ok := ticker.NextTick(); ok {
+		t.Error("next tick for a paused ticker")
+	}
+	ticker.Resume()
+	if _, ok := ticker.NextTick(); !ok {
+		t.Error("no next tick for a resumed ticker")
+	}
+	ticker.Stop()
+	if _, ok := ticker.NextTick(); ok {
+		t.Error("next tick for a stopped ticker")
+	}
+}
+
+func TestTickerPeriod(t *testing.T) {
+	const d = 50 * time.Millisecond
+	ticker := NewTicker(d, WithFixedDelay())
+	defer ticker.Stop()
+	if got := ticker.Period(); got != d {
+		t.Errorf("Period() = %v, want %v", got, d)
+	}
+	if left, ok := ticker.Interval(); !ok || left <= 0 || left > d {
+		t.Errorf("Interval() = %v, %v; want within %v", left, ok, d)
+	}
+	// Waiting for Ack, a fixed-delay ticker has no tick scheduled.
+	<-ticker.C
+	if left, ok := ticker.Interval(); ok {
+		t.Errorf("Interval() = %v before Ack", left)
+	}
+}
+
+func TestNewTickerContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	ticker := NewTickerContext(ctx, time.Millisecond)
+	<-ticker.C
+	cancel()
+	select {
+	case <-ticker.kt.exited:
+	case <-time.After(5 * time.Second):
+		t.Fatal("ticker still running after its context was cancelled")
+	}
+	ticker.Stop()
+}
+
+func TestNewTickerContextStopped(t *testing.T) {
+	before := runtime.NumGoroutine()
+	ticker := NewTickerContext(context.Background(), time.Millisecond)
+	ticker.Stop()
+	deadline := time.Now().Add(5 * time.Second)
+	for runtime.NumGoroutine() > before {
+		if time.Now().After(deadline) {
+			t.Fatal("context watcher outlived Stop")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestTickerStopPending(t *testing.T) {
+	ticker := NewTicker(time.Hour)
+	if ticker.Stop() {
+		t.Error("Stop reported a tick pending for a ticker yet to tick")
+	}
+
+	for _, opts := range [][]TickerOption{nil, {WithBuffer(4)}} {
+		ticker := NewTicker(time.Millisecond, opts...)
+		time.Sleep(10 * time.Millisecond)
+		if !ticker.Stop() {
+			t.Errorf("Stop with %d options reported no ticks pending", len(opts))
+		}
+		if ticker.Stop() {
+			t.Error("second Stop reported ticks pending")
+		}
+	}
+}
+
+func TestTickerCollectedGoroutine(t *testing.T) {
+	before := runtime.NumGoroutine()
+	func() {
+		ticker := NewTicker(time.Millisecond)
+		<-ticker.C
+	}()
+	deadline := time.Now().Add(5 * time.Second)
+	for runtime.NumGoroutine() > before {
+		if time.Now().After(deadline) {
+			t.Fatal("goroutine of a dropped ticker still running")
+		}
+		runtime.GC()
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestTickerSlack(t *testing.T) {
+	const d, slack = 20 * time.Millisecond, 10 * time.Millisecond
+	for _, opts := range [][]TickerOption{{WithSlack(slack)}, {WithSlack(slack), WithFixedRate()}} {
+		ticker := NewTicker(d, opts...)
+		for i := 0; i < 3; i++ {
+			next, ok := ticker.NextTick()
+			if !ok {
+				t.Fatal("no next tick")
+			}
+			// NextTick is read back from the time left, so allow a
+			// little either side of the grid.
+			if off := time.Duration(next) % slack; off > time.Millisecond && off < slack-time.Millisecond {
+				t.Errorf("next tick %v off the %v grid", off, slack)
+			}
+			due := <-ticker.C
+			if late := Now().Sub(due); late < 0 {
+				t.Errorf("tick received %v before it was due", -late)
+			}
+		}
+		ticker.Stop()
+	}
+}
@@ -0,0 +1,141 @@
+package monotime
+
+import (
+	"sync"
+	"time"
+)
+
+// FuncTicker calls a function at intervals on the monotonic clock. It is
+// returned by TickFunc and TickerFunc.
+//
+// The function runs on a goroutine of its own, apart from the one servicing
+// the kernel timer, so it may call Stop or Reset on its own FuncTicker. Calls
+// to the function never overlap, even across a Reset.
+type FuncTicker struct {
+	f  func()
+	fb func(Batch) // called instead of f, by TickerFunc
+
+	copy copyCheck
+
+	// call is held while f runs, keeping calls from overlapping when a
+	// Reset starts a new runner before the old call has returned.
+	call sync.Mutex
+
+	mu     sync.Mutex
+	ticker *Ticker
+	batch  *BatchTicker // instead of ticker, for TickerFunc
+	quit   chan struct{}
+}
+
+// TickFunc calls f every d, starting d from now, until the returned
+// FuncTicker is stopped. d must be greater than zero; if not, TickFunc will
+// panic. If a call to f takes longer than d, the ticks that came due in the
+// meantime are delivered once it returns.
+func TickFunc(d time.Duration, f func()) *FuncTicker {
+	if d <= 0 {
+		panic("non-positive interval for TickFunc")
+	}
+	t := &FuncTicker{f: f}
+	t.copy.init()
+	t.start(d)
+	return t
+}
+
+// TickerFunc is like TickFunc, but calls f once per Batch of expirations,
+// passing the time the latest came due and how many came due since the
+// previous call. A call that takes longer than d is then followed by a single
+// call for the ticks missed meanwhile, instead of one per tick.
+func TickerFunc(d time.Duration, f func(Batch)) *FuncTicker {
+	if d <= 0 {
+		panic("non-positive interval for TickerFunc")
+	}
+	t := &FuncTicker{fb: f}
+	t.copy.init()
+	t.start(d)
+	return t
+}
+
+// Stop turns off the ticker. Once Stop returns f will not be called again,
+// but Stop does not wait for a call already in progress; f may call Stop
+// itself. Calling Stop more than once has no further effect.
+func (t *FuncTicker) Stop() {
+	t.copy.check("FuncTicker.Stop")
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.stop()
+}
+
+// Reset stops the ticker and restarts it with period d, the next call being d
+// from now. It works on a stopped ticker too, and f may call Reset itself. d
+// must be greater than zero; if not, Reset will panic.
+func (t *FuncTicker) Reset(d time.Duration) {
+	if d <= 0 {
+		panic("non-positive interval for FuncTicker.Reset")
+	}
+	t.copy.check("FuncTicker.Reset")
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.stop()
+	t.start(d)
+}
+
+// start arms the ticker; t.mu must be held or t unpublished.
+func (t *FuncTicker) start(d time.Duration) {
+	t.quit = make(chan struct{})
+	if t.fb != nil {
+		t.batch = NewBatchTicker(d)
+		go t.run(nil, t.batch.C, t.quit)
+		return
+	}
+	t.ticker = NewTicker(d)
+	go t.run(t.ticker.C, nil, t.quit)
+}
+
+// stop disarms the ticker; t.mu must be held. It doesn't wait for the runner,
+// which may be the caller.
+func (t *FuncTicker) stop() {
+	if t.ticker == nil && t.batch == nil {
+		return
+	}
+	close(t.quit)
+	if t.batch != nil {
+		t.batch.Stop()
+	} else {
+		t.ticker.Stop()
+	}
+	t.ticker, t.batch = nil, nil
+}
+
+// run calls the function for each tick received on ticks, or batch received
+// on batches, until quit is closed; the other channel is nil.
+func (t *FuncTicker) run(ticks <-chan Time, batches <-chan Batch, quit <-chan struct{}) {
+	for {
+		var b Batch
+		select {
+		case <-ticks:
+		case b = <-batches:
+		case <-quit:
+			return
+		}
+
+		t.call.Lock()
+		// Checking quit under t.mu means a Stop either comes before the
+		// check, and f is skipped, or after it, and the call counts as
+		// already in progress.
+		t.mu.Lock()
+		select {
+		case <-quit:
+			t.mu.Unlock()
+			t.call.Unlock()
+			return
+		default:
+		}
+		t.mu.Unlock()
+		if t.fb != nil {
+			t.fb(b)
+		} else {
+			t.f()
+		}
+		t.call.Unlock()
+	}
+}
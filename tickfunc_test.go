@@ -0,0 +1,127 @@
+package monotime
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestTickFunc(t *testing.T) {
+	calls := make(chan struct{}, 100)
+	ticker := TickFunc(time.Millisecond, func() { calls <- struct{}{} })
+	defer ticker.Stop()
+	for i := 0; i < 5; i++ {
+		select {
+		case <-calls:
+		case <-time.After(time.Second):
+			t.Fatalf("call %d did not come", i)
+		}
+	}
+}
+
+func TestTickerFunc(t *testing.T) {
+	const d = time.Millisecond
+	batches := make(chan Batch, 100)
+	first := true
+	ticker := TickerFunc(d, func(b Batch) {
+		if first {
+			// Overrun, so the next call covers the ticks missed.
+			first = false
+			time.Sleep(10 * d)
+		}
+		batches <- b
+	})
+	defer ticker.Stop()
+
+	first1, next := <-batches, <-batches
+	if first1.Count != 1 {
+		t.Errorf("first call for %d ticks, want 1", first1.Count)
+	}
+	if next.Count < 2 {
+		t.Errorf("call after an overrun for %d ticks, want several", next.Count)
+	}
+	if got, want := next.Due.Sub(first1.Due), time.Duration(next.Count)*d; got != want {
+		t.Errorf("call after an overrun due %v after the first, want %v for %d ticks", got, want, next.Count)
+	}
+}
+
+func TestTickerFuncStopInside(t *testing.T) {
+	self := make(chan *FuncTicker, 1)
+	stopped := make(chan struct{})
+	self <- TickerFunc(time.Millisecond, func(Batch) {
+		// Once stopped there is no second call to block here.
+		(<-self).Stop()
+		close(stopped)
+	})
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("no call")
+	}
+}
+
+func TestTickFuncReset(t *testing.T) {
+	calls := make(chan Time, 100)
+	ticker := TickFunc(time.Hour, func() { calls <- Now() })
+	defer ticker.Stop()
+	reset := Now()
+	ticker.Reset(5 * time.Millisecond)
+	select {
+	case at := <-calls:
+		if d := at.Sub(reset); d < 5*time.Millisecond {
+			t.Errorf("call %v after Reset, before the new period", d)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no call after Reset")
+	}
+
+	// Reset restarts a stopped ticker too.
+	ticker.Stop()
+	ticker.Reset(time.Millisecond)
+	select {
+	case <-calls:
+	case <-time.After(time.Second):
+		t.Fatal("no call after Reset of a stopped ticker")
+	}
+}
+
+func TestTickFuncResetInside(t *testing.T) {
+	// A call may Reset its own ticker, and calls never overlap across it.
+	const n = 5
+	var running, count int32
+	calls := make(chan struct{}, n)
+	self := make(chan *FuncTicker, 1)
+	ticker := TickFunc(time.Millisecond, func() {
+		if atomic.AddInt32(&running, 1) > 1 {
+			t.Error("calls overlapped across Reset")
+		}
+		defer atomic.AddInt32(&running, -1)
+		if atomic.AddInt32(&count, 1) > n {
+			return
+		}
+		ticker := <-self
+		ticker.Reset(time.Millisecond)
+		self <- ticker
+		calls <- struct{}{}
+	})
+	self <- ticker
+	defer ticker.Stop()
+	for i := 0; i < n; i++ {
+		select {
+		case <-calls:
+		case <-time.After(time.Second):
+			t.Fatalf("call %d did not come after a Reset from inside", i)
+		}
+	}
+}
+
+func TestFuncTickerResetPanics(t *testing.T) {
+	ticker := TickFunc(time.Hour, func() {})
+	defer ticker.Stop()
+	defer func() {
+		if recover() == nil {
+			t.Error("Reset(0) did not panic")
+		}
+	}()
+	ticker.Reset(0)
+}
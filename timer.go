@@ -0,0 +1,151 @@
+package monotime
+
+import (
+	"sync"
+	"time"
+)
+
+// Timer represents a single event on the monotonic clock, driven by a kernel
+// timer. When the Timer expires, its deadline is sent on C, unless the Timer
+// was created by AfterFunc.
+type Timer struct {
+	// C receives the deadline the timer was set for when it expires, on
+	// the clock the timer runs on.
+	C <-chan Time
+
+	c     chan Time
+	f     func() // called instead of sending on c, if set
+	clock kernelClock
+	p     Precision
+
+	mu      sync.Mutex
+	kt      *kernelTimer
+	fired   bool // set by kt's goroutine; read only once it has exited
+	stopped bool
+	copy    copyCheck
+}
+
+// NewTimer returns a new Timer that expires d from now; it is NewTimerAt with
+// the deadline Now().Add(d). If d is not positive the Timer expires
+// immediately.
+func NewTimer(d time.Duration) *Timer {
+	return NewTimerAt(Now().Add(d))
+}
+
+// NewTimerAt returns a new Timer that expires when the monotonic clock reaches
+// t. The deadline is handed to the kernel as an absolute time, so no time is
+// lost converting it to a duration before arming. If t is not in the future
+// the Timer expires immediately.
+//
+// The timer has the default precision, which is Exact unless changed with
+// SetDefaultPrecision.
+func NewTimerAt(t Time) *Timer {
+	return NewTimerAtPrecision(t, DefaultPrecision())
+}
+
+// NewTimerAtPrecision is like NewTimerAt, but lets the Timer fire as late as
+// precision p allows.
+func NewTimerAtPrecision(t Time, p Precision) *Timer {
+	return newTimer(monotonicClock, t, p)
+}
+
+// After waits for d to pass on the monotonic clock and then sends the deadline
+// on the returned channel. It is equivalent to NewTimer(d).C. The kernel timer is
+// released once it fires, so unlike with a Timer nothing is left to stop, but
+// it can't be cancelled either; use NewTimer when the wait may be abandoned.
+func After(d time.Duration) <-chan Time {
+	return NewTimer(d).C
+}
+
+// AfterFunc waits for d to pass on the monotonic clock and then calls f in its
+// own goroutine. It returns a Timer whose Stop cancels the call and whose Reset
+// schedules it again; the Timer's C is nil.
+func AfterFunc(d time.Duration, f func()) *Timer {
+	timer := &Timer{f: f, clock: monotonicClock, p: DefaultPrecision()}
+	timer.copy.init()
+	timer.arm(Now().Add(d))
+	return timer
+}
+
+// newTimer returns a started Timer on clock c that expires at t, as late as p
+// allows.
+func newTimer(c kernelClock, t Time, p Precision) *Timer {
+	ch := make(chan Time, 1)
+	timer := &Timer{C: ch, c: ch, clock: c, p: p}
+	timer.copy.init()
+	timer.arm(t)
+	return timer
+}
+
+// arm starts a new kernel timer expiring at t; t.mu must be held or t
+// unpublished, and any previous kernel timer stopped.
+func (t *Timer) arm(at Time) {
+	kt, err := t.clock.newTimer()
+	if err != nil {
+		panic(err)
+	}
+	if err := kt.arm(t.p.Deadline(at), 0); err != nil {
+		kt.release()
+		panic(err)
+	}
+
+	t.kt, t.fired, t.stopped = kt, false, false
+	kt.start(func(uint64) bool {
+		if t.f != nil {
+			go t.f()
+		} else {
+			t.c <- at
+		}
+		t.fired = true
+		return false
+	})
+}
+
+// Stop prevents the Timer from firing, if it has not already, and releases
+// its kernel timer. A Timer that has fired has already released it, so Stop is
+// only needed to cancel one. Stop reports whether the Timer had fired with its
+// value still waiting on C, which a caller reusing the channel may want to
+// drain; for a Timer from AfterFunc it reports false. Stop does not close the
+// channel, to prevent a read from the channel succeeding incorrectly.
+// Calling Stop more than once has no further effect.
+func (t *Timer) Stop() bool {
+	t.copy.check("Timer.Stop")
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.kt.stop()
+	t.stopped = true
+	return len(t.c) > 0
+}
+
+// Reset changes the timer to expire d from now, on the clock it was created
+// on, whether or not it has expired or been stopped. It reports whether the
+// timer was still waiting to expire. Unlike time.Timer's, Reset discards an
+// expiration sent on C but not yet received, so a receive after Reset never
+// sees the old deadline.
+func (t *Timer) Reset(d time.Duration) bool {
+	t.copy.check("Timer.Reset")
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.reset(t.clock.now().Add(d))
+}
+
+// ResetAt is like Reset, but changes the timer to expire when its clock
+// reaches at.
+func (t *Timer) ResetAt(at Time) bool {
+	t.copy.check("Timer.ResetAt")
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.reset(at)
+}
+
+// reset rearms the timer to expire at at; t.mu must be held.
+func (t *Timer) reset(at Time) bool {
+	t.kt.stop()
+	active := !t.fired && !t.stopped
+	select {
+	case <-t.c:
+	default:
+	}
+	t.arm(at)
+	return active
+}
@@ -0,0 +1,32 @@
+package monotime
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimerReleasesAfterFiring(t *testing.T) {
+	before := openFDs(t)
+	for i := 0; i < 50; i++ {
+		<-NewTimerAt(Now()).C
+	}
+	waitFDs(t, before)
+}
+
+func TestTimerResetReleases(t *testing.T) {
+	before := openFDs(t)
+	timer := NewTimer(time.Hour)
+	for i := 0; i < 50; i++ {
+		timer.Reset(time.Hour)
+	}
+	timer.Stop()
+	waitFDs(t, before)
+}
+
+func TestAfterReleases(t *testing.T) {
+	before := openFDs(t)
+	for i := 0; i < 50; i++ {
+		<-After(0)
+	}
+	waitFDs(t, before)
+}
@@ -0,0 +1,165 @@
+package monotime
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimerFires(t *testing.T) {
+	start := Now()
+	deadline := start.Add(5 * time.Millisecond)
+	timer := NewTimerAt(deadline)
+	select {
+	case got := <-timer.C:
+		if got != deadline {
+			t.Errorf("timer sent %v, want its deadline %v", got, deadline)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timer did not fire")
+	}
+	if d := Now().Sub(start); d < 5*time.Millisecond {
+		t.Errorf("timer fired after %v, before its deadline", d)
+	}
+	timer.Stop()
+	timer.Stop()
+}
+
+func TestTimerStopBeforeFiring(t *testing.T) {
+	timer := NewTimerAt(Now().Add(time.Hour))
+	timer.Stop()
+	select {
+	case <-timer.C:
+		t.Fatal("stopped timer fired")
+	case <-time.After(10 * time.Millisecond):
+	}
+}
+
+func TestTimerPast(t *testing.T) {
+	timer := NewTimerAt(Now().Add(-time.Second))
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-time.After(time.Second):
+		t.Fatal("timer in the past did not fire")
+	}
+}
+
+func TestNewTimer(t *testing.T) {
+	start := Now()
+	timer := NewTimer(5 * time.Millisecond)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-time.After(time.Second):
+		t.Fatal("timer did not fire")
+	}
+	if d := Now().Sub(start); d < 5*time.Millisecond {
+		t.Errorf("timer fired after %v, before its deadline", d)
+	}
+}
+
+func TestTimerReset(t *testing.T) {
+	timer := NewTimer(time.Hour)
+	defer timer.Stop()
+	if !timer.Reset(time.Millisecond) {
+		t.Error("Reset of a waiting timer reported it inactive")
+	}
+	select {
+	case <-timer.C:
+	case <-time.After(time.Second):
+		t.Fatal("reset timer did not fire")
+	}
+
+	if timer.Reset(time.Millisecond) {
+		t.Error("Reset of an expired timer reported it active")
+	}
+	<-timer.C
+
+	timer.Stop()
+	if timer.ResetAt(Now().Add(time.Millisecond)) {
+		t.Error("ResetAt of a stopped timer reported it active")
+	}
+	<-timer.C
+}
+
+func TestTimerResetDiscardsExpiration(t *testing.T) {
+	timer := NewTimerAt(Now())
+	defer timer.Stop()
+	// Wait for the expiration to be sent, but leave it unreceived.
+	for len(timer.C) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	timer.Reset(time.Hour)
+	select {
+	case <-timer.C:
+		t.Fatal("received the expiration from before Reset")
+	case <-time.After(10 * time.Millisecond):
+	}
+}
+
+func TestAfter(t *testing.T) {
+	start := Now()
+	select {
+	case <-After(5 * time.Millisecond):
+	case <-time.After(time.Second):
+		t.Fatal("After did not fire")
+	}
+	if d := Now().Sub(start); d < 5*time.Millisecond {
+		t.Errorf("After fired after %v, before its deadline", d)
+	}
+}
+
+func TestAfterFunc(t *testing.T) {
+	called := make(chan Time, 1)
+	start := Now()
+	timer := AfterFunc(5*time.Millisecond, func() { called <- Now() })
+	defer timer.Stop()
+	if timer.C != nil {
+		t.Error("AfterFunc timer has a channel")
+	}
+	select {
+	case at := <-called:
+		if d := at.Sub(start); d < 5*time.Millisecond {
+			t.Errorf("f called after %v, before its deadline", d)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("f was not called")
+	}
+
+	if timer.Reset(time.Millisecond) {
+		t.Error("Reset after the call reported the timer active")
+	}
+	select {
+	case <-called:
+	case <-time.After(time.Second):
+		t.Fatal("f was not called again after Reset")
+	}
+}
+
+func TestAfterFuncStop(t *testing.T) {
+	called := make(chan struct{}, 1)
+	timer := AfterFunc(10*time.Millisecond, func() { called <- struct{}{} })
+	timer.Stop()
+	select {
+	case <-called:
+		t.Fatal("f called after Stop")
+	case <-time.After(30 * time.Millisecond):
+	}
+}
+
+func TestTimerStopPending(t *testing.T) {
+	timer := NewTimer(time.Hour)
+	if timer.Stop() {
+		t.Error("Stop reported an expiration pending for a timer yet to fire")
+	}
+
+	timer = NewTimer(time.Millisecond)
+	time.Sleep(10 * time.Millisecond)
+	if !timer.Stop() {
+		t.Error("Stop reported no expiration pending for a fired timer")
+	}
+	<-timer.C
+	if timer.Stop() {
+		t.Error("Stop reported an expiration pending once it was received")
+	}
+}
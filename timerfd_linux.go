@@ -0,0 +1,270 @@
+package monotime
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+	"sync"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// kernelTimer is a kernel timer together with the goroutine waiting on it. It
+// is the engine shared by Ticker and Timer; on Linux it is a timerfd, which
+// the goroutine reads through the runtime's poller, so a timer waiting to
+// expire parks its goroutine rather than holding an OS thread in a system
+// call.
+type kernelTimer struct {
+	fd   int      // timerfd, non-blocking
+	file *os.File // fd, registered with the runtime's poller
+
+	mu     sync.Mutex
+	closed bool // whether the descriptor has been released
+
+	pooled   *pooledTimer // set if serviced by the shared poller
+	ring     *uring       // set, and fd unused, if driven by io_uring
+	external bool         // the caller reads fd; there is no goroutine
+
+	stopOnce sync.Once
+	done     chan struct{}
+	exited   chan struct{}
+	errc     chan error // receives the error that ended the goroutine, if any
+}
+
+// newKernelTimer returns a kernelTimer on the monotonic clock.
+func newKernelTimer() (*kernelTimer, error) {
+	return newTimerfd(unix.CLOCK_MONOTONIC)
+}
+
+// newTimerfd returns a kernelTimer on the given clock. Timers on the alarm
+// clocks need CAP_WAKE_ALARM, whose absence is reported as a
+// *CapabilityError before the kernel is asked.
+func newTimerfd(clockid int) (*kernelTimer, error) {
+	alarm := clockid == unix.CLOCK_REALTIME_ALARM || clockid == unix.CLOCK_BOOTTIME_ALARM
+	if alarm {
+		if err := CheckWakeAlarm(); err != nil {
+			return nil, err
+		}
+	}
+	fd, err := unix.TimerfdCreate(clockid, unix.TFD_CLOEXEC|unix.TFD_NONBLOCK)
+	if alarm && errors.Is(err, unix.EPERM) {
+		return nil, &CapabilityError{Op: "create wake alarm timer", Capability: "CAP_WAKE_ALARM"}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Error creating timerfd: %w", err)
+	}
+	return newPolledTimer(fd, "timerfd")
+}
+
+// newPolledTimer returns a kernelTimer reading expirations from the
+// non-blocking descriptor fd, which it takes ownership of.
+func newPolledTimer(fd int, name string) (*kernelTimer, error) {
+	file := os.NewFile(uintptr(fd), name)
+	// Only a file the runtime's poller accepted can have a deadline; any
+	// other would make the goroutine spin on EAGAIN.
+	if err := file.SetReadDeadline(time.Time{}); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("Error registering %s with the poller: %w", name, err)
+	}
+	return &kernelTimer{
+		fd:     fd,
+		file:   file,
+		done:   make(chan struct{}),
+		exited: make(chan struct{}),
+		errc:   make(chan error, 1),
+	}, nil
+}
+
+// arm sets the timer to first expire at the monotonic time at, and then
+// every interval; an interval of zero makes it one-shot. Times that are not
+// in the future expire immediately.
+func (f *kernelTimer) arm(at Time, interval time.Duration) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.closed {
+		return nil
+	}
+	if f.ring != nil {
+		return f.ring.arm(at, interval)
+	}
+	if at <= 0 {
+		// A zero it_value would disarm the timer rather than fire it.
+		at = 1
+	}
+	spec := unix.ItimerSpec{
+		Interval: unix.NsecToTimespec(int64(interval)),
+		Value:    unix.NsecToTimespec(int64(at)),
+	}
+	if err := unix.TimerfdSettime(f.fd, unix.TFD_TIMER_ABSTIME, &spec, nil); err != nil {
+		return fmt.Errorf("Error arming timerfd: %w", err)
+	}
+	return nil
+}
+
+// disarm stops the timer expiring until it is armed again, discarding any
+// expirations not yet read.
+func (f *kernelTimer) disarm() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.closed {
+		return nil
+	}
+	if f.ring != nil {
+		// A timeout already pending finds nothing due.
+		f.ring.sched.clear()
+		return nil
+	}
+	if err := unix.TimerfdSettime(f.fd, 0, &unix.ItimerSpec{}, nil); err != nil {
+		return fmt.Errorf("Error disarming timerfd: %w", err)
+	}
+	return nil
+}
+
+// remaining returns the time until the timer next expires, and false if it is
+// not armed or has been released.
+func (f *kernelTimer) remaining() (time.Duration, bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.closed {
+		return 0, false, nil
+	}
+	if f.ring != nil {
+		left, armed := f.ring.sched.remaining()
+		return left, armed, nil
+	}
+	var spec unix.ItimerSpec
+	if err := unix.TimerfdGettime(f.fd, &spec); err != nil {
+		return 0, false, fmt.Errorf("Error reading timerfd: %w", err)
+	}
+	left := time.Duration(spec.Value.Nano())
+	return left, left != 0, nil
+}
+
+// start runs fire on a new goroutine each time the timer expires, with the
+// number of expirations since the last call, until fire returns false or stop
+// is called. The timer is released when the goroutine exits, so one-shot
+// timers don't hold their descriptors until stopped.
+func (f *kernelTimer) start(fire func(n uint64) bool) {
+	go f.run(fire)
+}
+
+// startExternal marks the timer as read by the caller, through its file,
+// rather than a goroutine of its own.
+func (f *kernelTimer) startExternal() {
+	f.external = true
+}
+
+// startLocked is like start, but runs fire on a goroutine locked to its own OS
+// thread, with its CPU affinity set to cpus if any are given. The goroutine
+// never unlocks, so the runtime discards the thread when it exits rather than
+// reusing it with the affinity still set. If the affinity cannot be set the
+// goroutine is not started and the timer must be released.
+func (f *kernelTimer) startLocked(cpus []int, fire func(n uint64) bool) error {
+	for _, cpu := range cpus {
+		if cpu < 0 || cpu >= len(unix.CPUSet{})*64 {
+			return fmt.Errorf("Error setting CPU affinity of timer thread: no CPU %d", cpu)
+		}
+	}
+	started := make(chan error, 1)
+	go func() {
+		runtime.LockOSThread()
+		if len(cpus) > 0 {
+			var set unix.CPUSet
+			for _, cpu := range cpus {
+				set.Set(cpu)
+			}
+			if err := unix.SchedSetaffinity(0, &set); err != nil {
+				started <- fmt.Errorf("Error setting CPU affinity of timer thread: %w", err)
+				return
+			}
+		}
+		started <- nil
+		f.run(fire)
+	}()
+	return <-started
+}
+
+// stop ends the goroutine and releases the timer. Once stop returns fire is
+// not running and will not be called again. stop must not be called from
+// within fire.
+func (f *kernelTimer) stop() {
+	f.stopOnce.Do(func() {
+		close(f.done)
+		if f.external {
+			f.release()
+			close(f.exited)
+			return
+		}
+		if f.pooled != nil {
+			f.pooled.stop()
+			<-f.exited
+			return
+		}
+		f.mu.Lock()
+		if f.ring != nil && !f.closed {
+			f.ring.wake()
+		} else if !f.closed {
+			// A deadline in the past wakes the goroutine from its
+			// read.
+			f.file.SetReadDeadline(time.Unix(1, 0))
+		}
+		f.mu.Unlock()
+		<-f.exited
+	})
+}
+
+// release closes the descriptor of a timer whose goroutine is not running.
+// Only once the goroutine is gone can it be closed, so its number can't be
+// reused by another file while arm and disarm may still use it. Calling
+// release more than once has no further effect.
+func (f *kernelTimer) release() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.closed {
+		return
+	}
+	f.closed = true
+	if f.ring != nil {
+		f.ring.close()
+		return
+	}
+	f.file.Close()
+}
+
+func (f *kernelTimer) run(fire func(n uint64) bool) {
+	defer close(f.exited)
+	defer f.release()
+	if f.ring != nil {
+		f.runRing(fire)
+		return
+	}
+
+	var buf [8]byte
+	for {
+		// The poller parks the read until the timerfd is readable,
+		// and retries it should another reader or a re-arm have
+		// emptied it first.
+		r, err := f.file.Read(buf[:])
+		select {
+		case <-f.done:
+			return
+		default:
+		}
+		if err != nil {
+			f.fail(fmt.Errorf("Error reading timerfd: %w", err))
+			return
+		}
+		if r != len(buf) {
+			f.fail(fmt.Errorf("Error reading timerfd: read %d bytes of %d", r, len(buf)))
+			return
+		}
+
+		// The kernel writes the number of expirations since the last
+		// read as a host-order uint64.
+		if !fire(hostOrder.Uint64(buf[:])) {
+			return
+		}
+	}
+}
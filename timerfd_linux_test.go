@@ -0,0 +1,96 @@
+package monotime
+
+import (
+	"bytes"
+	"io/ioutil"
+	"strconv"
+	"testing"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// threads returns the number of OS threads in the process.
+func threads(t *testing.T) int {
+	t.Helper()
+	status, err := ioutil.ReadFile("/proc/self/status")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range bytes.Split(status, []byte("\n")) {
+		if v := bytes.TrimPrefix(line, []byte("Threads:")); len(v) < len(line) {
+			n, err := strconv.Atoi(string(bytes.TrimSpace(v)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			return n
+		}
+	}
+	t.Fatal("no thread count in /proc/self/status")
+	return 0
+}
+
+func TestTickersParkOnPoller(t *testing.T) {
+	before := threads(t)
+	for i := 0; i < 50; i++ {
+		ticker := NewTicker(time.Hour)
+		defer ticker.Stop()
+	}
+	time.Sleep(10 * time.Millisecond)
+	// A goroutine blocked in read(2) would hold a thread apiece.
+	if after := threads(t); after >= before+50 {
+		t.Errorf("%d threads for 50 idle tickers, up from %d", after, before)
+	}
+}
+
+func TestKernelTimerShortRead(t *testing.T) {
+	var p [2]int
+	if err := unix.Pipe2(p[:], unix.O_NONBLOCK|unix.O_CLOEXEC); err != nil {
+		t.Fatal(err)
+	}
+	defer unix.Close(p[1])
+	kt, err := newPolledTimer(p[0], "pipe")
+	if err != nil {
+		unix.Close(p[0])
+		t.Fatal(err)
+	}
+	defer kt.stop()
+	kt.start(func(uint64) bool {
+		t.Error("expiration decoded from a short read")
+		return true
+	})
+
+	// Too few bytes for an expiration count.
+	unix.Write(p[1], []byte{1, 2, 3, 4})
+	select {
+	case err := <-kt.errc:
+		if err == nil {
+			t.Fatal("nil error from a short read")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no error from a short read")
+	}
+}
+
+func TestKernelTimerStopWakesRead(t *testing.T) {
+	kt, err := newKernelTimer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	kt.start(func(uint64) bool { return true })
+	stopped := make(chan struct{})
+	go func() {
+		kt.stop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("stop did not wake an unarmed timer's goroutine")
+	}
+	select {
+	case err := <-kt.errc:
+		t.Errorf("stop reported as a failure: %v", err)
+	default:
+	}
+}
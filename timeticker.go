@@ -0,0 +1,121 @@
+package monotime
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// TimeTicker is a drop-in replacement for time.Ticker driven by a kernel
+// monotonic timer. Its channel carries time.Time values and its method set
+// matches time.Ticker, so existing code can switch by changing only the
+// constructor.
+//
+// The time delivered is the time.Now reading taken when the kernel timer
+// fired; it carries both a wall clock approximation and Go's own monotonic
+// reading, exactly as the values from time.Ticker do. As with time.Ticker,
+// ticks are dropped rather than queued for a slow receiver.
+type TimeTicker struct {
+	// C is the channel on which the ticks are delivered.
+	C <-chan time.Time
+
+	c chan time.Time
+
+	copy      copyCheck
+	resetting int32 // atomic; concurrent Reset calls, in checked mode
+	stopped   bool
+
+	mu     sync.Mutex
+	ticker *Ticker
+	quit   chan struct{}
+	exited chan struct{}
+}
+
+// NewTimeTicker returns a new TimeTicker containing a channel that will send
+// the current time after each tick. The period of the ticks is specified by
+// the duration argument. d must be greater than zero; if not, NewTimeTicker
+// will panic. Stop the ticker to release associated resources.
+func NewTimeTicker(d time.Duration) *TimeTicker {
+	if d <= 0 {
+		panic("non-positive interval for NewTimeTicker")
+	}
+	c := make(chan time.Time, 1)
+	t := &TimeTicker{C: c, c: c}
+	t.copy.init()
+	t.start(d)
+	return t
+}
+
+// Stop turns off a ticker. After Stop, no more ticks will be sent. Stop does
+// not close the channel, to prevent a concurrent goroutine reading from the
+// channel from seeing an erroneous "tick".
+func (t *TimeTicker) Stop() {
+	t.copy.check("TimeTicker.Stop")
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.stopped = true
+	t.stop()
+}
+
+// Reset stops a ticker and resets its period to the specified duration. The
+// next tick will arrive after the new period elapses. d must be greater than
+// zero; if not, Reset will panic.
+func (t *TimeTicker) Reset(d time.Duration) {
+	if d <= 0 {
+		panic("non-positive interval for TimeTicker.Reset")
+	}
+	if checksEnabled() {
+		t.copy.check("TimeTicker.Reset")
+		if atomic.AddInt32(&t.resetting, 1) > 1 {
+			reportMisuse("TimeTicker.Reset", "called concurrently with another Reset")
+		}
+		defer atomic.AddInt32(&t.resetting, -1)
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.stopped && checksEnabled() {
+		reportMisuse("TimeTicker.Reset", "called after Stop")
+	}
+	t.stopped = false
+	t.stop()
+	t.start(d)
+}
+
+func (t *TimeTicker) start(d time.Duration) {
+	t.ticker = NewTicker(d)
+	t.quit = make(chan struct{})
+	t.exited = make(chan struct{})
+	go t.forward(t.ticker, t.quit, t.exited)
+}
+
+func (t *TimeTicker) stop() {
+	if t.ticker == nil {
+		return
+	}
+	close(t.quit)
+	<-t.exited
+	t.ticker.Stop()
+	t.ticker = nil
+
+	// Discard a tick left over from before the Stop or Reset so the
+	// receiver never sees a stale value.
+	select {
+	case <-t.c:
+	default:
+	}
+}
+
+func (t *TimeTicker) forward(ticker *Ticker, quit <-chan struct{}, exited chan<- struct{}) {
+	defer close(exited)
+	for {
+		select {
+		case <-ticker.C:
+		case <-quit:
+			return
+		}
+		select {
+		case t.c <- time.Now():
+		default:
+		}
+	}
+}
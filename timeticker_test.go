@@ -0,0 +1,87 @@
+package monotime
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimeTicker(t *testing.T) {
+	const d = 2 * time.Millisecond
+	ticker := NewTimeTicker(d)
+	defer ticker.Stop()
+	prev := time.Now()
+	for i := 1; i <= 5; i++ {
+		select {
+		case tick := <-ticker.C:
+			if tick.Before(prev) {
+				t.Errorf("tick %d at %v, before the previous one at %v", i, tick, prev)
+			}
+			prev = tick
+		case <-time.After(time.Second):
+			t.Fatalf("tick %d did not arrive", i)
+		}
+	}
+}
+
+func TestTimeTickerStop(t *testing.T) {
+	ticker := NewTimeTicker(time.Millisecond)
+	<-ticker.C
+	time.Sleep(5 * time.Millisecond)
+	ticker.Stop()
+	ticker.Stop()
+	// A tick left over from before Stop is discarded too.
+	select {
+	case <-ticker.C:
+		t.Fatal("tick after Stop")
+	case <-time.After(10 * time.Millisecond):
+	}
+}
+
+func TestTimeTickerReset(t *testing.T) {
+	ticker := NewTimeTicker(time.Hour)
+	defer ticker.Stop()
+	start := time.Now()
+	ticker.Reset(5 * time.Millisecond)
+	select {
+	case <-ticker.C:
+		if d := time.Since(start); d < 5*time.Millisecond {
+			t.Errorf("tick %v after Reset, before the new period", d)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no tick after Reset")
+	}
+
+	// Reset also restarts a stopped ticker, as time.Ticker's does.
+	ticker.Stop()
+	ticker.Reset(time.Millisecond)
+	select {
+	case <-ticker.C:
+	case <-time.After(time.Second):
+		t.Fatal("no tick after Reset of a stopped ticker")
+	}
+}
+
+func TestTimeTickerResetAfterStopChecked(t *testing.T) {
+	reports := collectMisuse(t)
+	ticker := NewTimeTicker(time.Hour)
+	defer ticker.Stop()
+	ticker.Stop()
+	ticker.Reset(time.Hour)
+	select {
+	case err := <-reports:
+		if err.Op != "TimeTicker.Reset" {
+			t.Errorf("reported %s, want TimeTicker.Reset", err.Op)
+		}
+	default:
+		t.Error("Reset after Stop was not reported")
+	}
+}
+
+func TestNewTimeTickerPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("NewTimeTicker(0) did not panic")
+		}
+	}()
+	NewTimeTicker(0)
+}
@@ -0,0 +1,160 @@
+// Package tracecorr correlates monotime timestamps with the timestamps in
+// kernel traces, so application spans can be laid over ftrace or perf
+// timelines.
+//
+// Traces are usually stamped with the kernel's trace clock (ftrace's "local"
+// clock, perf's default), which clock_gettime cannot read. tracecorr pins the
+// two together with markers: Mark writes the current monotonic time into the
+// trace through trace_marker, the kernel stamps that event with its trace
+// clock, and Fit turns the resulting pairs into a linear mapping between the
+// clocks, correcting for drift as well as offset.
+package tracecorr
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/thisguycodes/monotime"
+)
+
+// MarkerPath is the ftrace marker file Mark writes to by default.
+const MarkerPath = "/sys/kernel/tracing/trace_marker"
+
+const markerPrefix = "monotime-sync mono="
+
+// Pair is one marker: the same instant on both clocks, in nanoseconds.
+type Pair struct {
+	Trace int64
+	Mono  monotime.Time
+}
+
+// Mark writes a correlation marker carrying the current monotonic time to w,
+// which should be an ftrace trace_marker file. The kernel stamps the marker
+// while the write is in progress; the returned uncertainty is how long the
+// write took, bounding the error of the pair.
+func Mark(w io.Writer) (uncertainty time.Duration, err error) {
+	start := monotime.Now()
+	_, err = fmt.Fprintf(w, "%s%d\n", markerPrefix, int64(start))
+	return monotime.Since(start), err
+}
+
+// OpenMarker opens the ftrace marker file for Mark.
+func OpenMarker() (*os.File, error) {
+	f, err := os.OpenFile(MarkerPath, os.O_WRONLY, 0)
+	if os.IsNotExist(err) {
+		// Older kernels only mount tracefs under debugfs.
+		f, err = os.OpenFile("/sys/kernel/debug/tracing/trace_marker", os.O_WRONLY, 0)
+	}
+	return f, err
+}
+
+// markerLine matches the trace timestamp and payload of a marker in ftrace
+// text output ("... 12345.678901: tracing_mark_write: monotime-sync
+// mono=...") and perf script output ("... 12345.678901234: ftrace:print:
+// ...").
+var markerLine = regexp.MustCompile(`\s(\d+)\.(\d+):.*` + regexp.QuoteMeta(markerPrefix) + `(\d+)`)
+
+// ParseMarkers scans trace text output for markers written by Mark.
+func ParseMarkers(r io.Reader) ([]Pair, error) {
+	var pairs []Pair
+	s := bufio.NewScanner(r)
+	s.Buffer(make([]byte, 64*1024), 1024*1024)
+	for s.Scan() {
+		m := markerLine.FindStringSubmatch(s.Text())
+		if m == nil {
+			continue
+		}
+		trace, err := parseSeconds(m[1], m[2])
+		if err != nil {
+			return nil, err
+		}
+		mono, err := strconv.ParseInt(m[3], 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		pairs = append(pairs, Pair{Trace: trace, Mono: monotime.Time(mono)})
+	}
+	return pairs, s.Err()
+}
+
+// parseSeconds parses a "seconds.fraction" trace timestamp into nanoseconds
+// without going through float64.
+func parseSeconds(sec, frac string) (int64, error) {
+	s, err := strconv.ParseInt(sec, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	if len(frac) > 9 {
+		frac = frac[:9]
+	}
+	f, err := strconv.ParseInt(frac+strings.Repeat("0", 9-len(frac)), 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	return s*1e9 + f, nil
+}
+
+// Correlation maps trace clock timestamps to monotonic time and back:
+// mono = Offset + trace*(1+Skew).
+type Correlation struct {
+	// Offset is the monotonic time at trace clock zero.
+	Offset time.Duration
+	// Skew is the rate difference between the clocks, e.g. 1e-6 when the
+	// monotonic clock runs one part per million fast.
+	Skew float64
+}
+
+// Fit returns the least-squares Correlation for pairs. With a single pair
+// only the offset can be determined.
+func Fit(pairs []Pair) (Correlation, error) {
+	if len(pairs) == 0 {
+		return Correlation{}, errors.New("no correlation markers")
+	}
+
+	// Work relative to the first pair so float64 keeps nanosecond
+	// precision over long traces.
+	t0, m0 := pairs[0].Trace, int64(pairs[0].Mono)
+	var sx, sy float64
+	for _, p := range pairs {
+		sx += float64(p.Trace - t0)
+		sy += float64(int64(p.Mono) - m0)
+	}
+	n := float64(len(pairs))
+	mx, my := sx/n, sy/n
+
+	var sxx, sxy float64
+	for _, p := range pairs {
+		dx := float64(p.Trace-t0) - mx
+		dy := float64(int64(p.Mono)-m0) - my
+		sxx += dx * dx
+		sxy += dx * dy
+	}
+	slope := 1.0
+	if sxx > 0 {
+		slope = sxy / sxx
+	}
+
+	// mono = m0 + my + slope*(trace - t0 - mx); regroup so the large
+	// values only meet the small skew term.
+	skew := slope - 1
+	offset := float64(m0-t0) + my - slope*mx - skew*float64(t0)
+	return Correlation{Offset: time.Duration(offset), Skew: skew}, nil
+}
+
+// ToMono converts a trace clock timestamp in nanoseconds to monotonic time.
+func (c Correlation) ToMono(trace int64) monotime.Time {
+	return monotime.Time(int64(c.Offset) + trace + int64(float64(trace)*c.Skew))
+}
+
+// FromMono converts a monotonic time to a trace clock timestamp in
+// nanoseconds.
+func (c Correlation) FromMono(t monotime.Time) int64 {
+	return int64(float64(int64(t)-int64(c.Offset)) / (1 + c.Skew))
+}
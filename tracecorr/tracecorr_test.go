@@ -0,0 +1,83 @@
+package tracecorr
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/thisguycodes/monotime"
+)
+
+func TestFit(t *testing.T) {
+	// The monotonic clock runs 2ppm fast and 5s ahead of the trace clock.
+	const skew, offset = 2e-6, 5 * time.Second
+	var pairs []Pair
+	for i := int64(0); i < 10; i++ {
+		trace := 1000e9 + i*60e9
+		mono := int64(offset) + trace + int64(float64(trace)*skew)
+		pairs = append(pairs, Pair{Trace: trace, Mono: monotime.Time(mono)})
+	}
+	c, err := Fit(pairs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(c.Skew-skew) > 1e-9 {
+		t.Errorf("Skew = %v, want %v", c.Skew, skew)
+	}
+	for _, p := range pairs {
+		if d := time.Duration(c.ToMono(p.Trace) - p.Mono); d < -time.Microsecond || d > time.Microsecond {
+			t.Errorf("ToMono(%d) off by %v", p.Trace, d)
+		}
+		if d := c.FromMono(p.Mono) - p.Trace; d < -1000 || d > 1000 {
+			t.Errorf("FromMono(%d) off by %dns", p.Mono, d)
+		}
+	}
+}
+
+func TestFitSinglePair(t *testing.T) {
+	c, err := Fit([]Pair{{Trace: 100, Mono: 250}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Skew != 0 || c.Offset != 150 {
+		t.Errorf("Fit of one pair = %+v, want offset 150 and no skew", c)
+	}
+	if _, err := Fit(nil); err == nil {
+		t.Error("Fit(nil) succeeded")
+	}
+}
+
+func TestParseMarkers(t *testing.T) {
+	trace := strings.Join([]string{
+		"# tracer: nop",
+		"  app-1234  [002] .... 12345.678901: tracing_mark_write: monotime-sync mono=42",
+		"  app-1234  [002] .... 12346.5: sched_switch: prev_comm=app",
+		"   app 1234 [002] 12347.000000123: ftrace:print: monotime-sync mono=43",
+	}, "\n")
+	pairs, err := ParseMarkers(strings.NewReader(trace))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Pair{{Trace: 12345678901000, Mono: 42}, {Trace: 12347000000123, Mono: 43}}
+	if len(pairs) != len(want) {
+		t.Fatalf("got %+v", pairs)
+	}
+	for i := range want {
+		if pairs[i] != want[i] {
+			t.Errorf("pair %d = %+v, want %+v", i, pairs[i], want[i])
+		}
+	}
+}
+
+func TestMark(t *testing.T) {
+	var buf bytes.Buffer
+	if _, err := Mark(&buf); err != nil {
+		t.Fatal(err)
+	}
+	pairs, err := ParseMarkers(strings.NewReader(" 1.0: " + buf.String()))
+	if err != nil || len(pairs) != 1 || pairs[0].Mono == 0 {
+		t.Errorf("marker %q parsed as %+v, %v", buf.String(), pairs, err)
+	}
+}
@@ -0,0 +1,22 @@
+//go:build darwin || linux || openbsd || windows
+// +build darwin linux openbsd windows
+
+package monotime
+
+import "time"
+
+// Uptime returns how long the system has been up, including time spent
+// suspended: the reading of the clock NowBoottime reads, which starts at boot.
+func Uptime() time.Duration {
+	return time.Duration(NowBoottime())
+}
+
+// BootTime estimates when the system booted on the wall clock, as the wall
+// time now less Uptime. It moves with the wall clock, so stepping the clock
+// moves it too; it is for showing people, not for measuring. The result has
+// no monotonic clock reading.
+func BootTime() time.Time {
+	wall := time.Now()
+	up := Uptime()
+	return wall.Round(0).Add(-up)
+}
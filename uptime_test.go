@@ -0,0 +1,36 @@
+//go:build darwin || linux || openbsd || windows
+// +build darwin linux openbsd windows
+
+package monotime
+
+import (
+	"testing"
+	"time"
+)
+
+func TestUptime(t *testing.T) {
+	// The boottime clock only gains on the monotonic clock, so read it
+	// second.
+	mono := Now().Sub(0)
+	up := Uptime()
+	if up <= 0 {
+		t.Fatalf("Uptime() = %v, want positive", up)
+	}
+	if up < mono {
+		t.Errorf("Uptime() = %v, less than the monotonic clock's %v", up, mono)
+	}
+}
+
+func TestBootTime(t *testing.T) {
+	first := BootTime()
+	if !first.Before(time.Now()) {
+		t.Errorf("BootTime() = %v, not in the past", first)
+	}
+	time.Sleep(10 * time.Millisecond)
+	if d := BootTime().Sub(first); d < -time.Millisecond || d > time.Millisecond {
+		t.Errorf("BootTime moved %v in 10ms", d)
+	}
+	if first != first.Round(0) {
+		t.Error("BootTime has a monotonic clock reading")
+	}
+}
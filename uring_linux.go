@@ -0,0 +1,301 @@
+package monotime
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// WithIOUring drives the ticker's kernel timer with IORING_OP_TIMEOUT requests
+// on an io_uring instance of its own instead of a timerfd. Its goroutine
+// submits each tick's timeout and waits for the next in a single
+// io_uring_enter call, one system call per tick, at the price of holding an OS
+// thread while it waits rather than parking on the runtime's poller. Only
+// ClockMonotonic is supported. NewTicker fails on other clocks, or where
+// io_uring is missing or blocked, as Capabilities reports.
+func WithIOUring() TickerOption {
+	return func(cfg *tickerConfig) {
+		cfg.newTimer = newUringTimer
+	}
+}
+
+// io_uring ABI constants, from linux/io_uring.h.
+const (
+	ioringOpNop           = 0
+	ioringOpTimeout       = 11
+	ioringOpTimeoutRemove = 12
+
+	ioringEnterGetevents = 1
+
+	ioringOffSQRing = 0
+	ioringOffCQRing = 0x8000000
+	ioringOffSQEs   = 0x10000000
+
+	// uringEntries is the size of the submission queue. A timer has at
+	// most a timeout, its removal and a wakeup queued at once.
+	uringEntries = 8
+)
+
+// User data of requests other than timeouts, whose user data counts up from
+// one.
+const (
+	uringRemoveTag = 1 << 63
+	uringWakeTag   = 1<<63 | 1
+)
+
+type uringParams struct {
+	sqEntries    uint32
+	cqEntries    uint32
+	flags        uint32
+	sqThreadCPU  uint32
+	sqThreadIdle uint32
+	features     uint32
+	wqFd         uint32
+	resv         [3]uint32
+	sqOff        struct {
+		head, tail, ringMask, ringEntries, flags, dropped, array, resv1 uint32
+		resv2                                                           uint64
+	}
+	cqOff struct {
+		head, tail, ringMask, ringEntries, overflow, cqes, flags, resv1 uint32
+		resv2                                                           uint64
+	}
+}
+
+type uringSQE struct {
+	opcode      uint8
+	flags       uint8
+	ioprio      uint16
+	fd          int32
+	off         uint64
+	addr        uint64
+	len         uint32
+	opFlags     uint32
+	userData    uint64
+	bufIndex    uint16
+	personality uint16
+	spliceFdIn  int32
+	pad         [2]uint64
+}
+
+type uringCQE struct {
+	userData uint64
+	res      int32
+	flags    uint32
+}
+
+// kernelTimespec is struct __kernel_timespec, 64-bit on every architecture.
+type kernelTimespec struct {
+	sec, nsec int64
+}
+
+// uring is an io_uring instance driving one kernel timer as a series of
+// relative timeouts, kept on the grid by a relSchedule.
+type uring struct {
+	fd    int
+	sched relSchedule
+
+	sqRing, cqRing, sqeMem []byte // the kernel's mappings
+	sqTail, sqMask         *uint32
+	sqArray                unsafe.Pointer // uint32 per entry
+	cqHead, cqTail, cqMask *uint32
+	cqes                   unsafe.Pointer
+
+	mu       sync.Mutex
+	pending  uint32         // requests queued but not yet submitted
+	seq      uint64         // user data of the latest timeout
+	inFlight bool           // whether that timeout may still complete
+	ts       kernelTimespec // its duration, read by the kernel on submission
+}
+
+// newUringTimer returns a kernelTimer on clock c driven by io_uring.
+func newUringTimer(c Clock) (*kernelTimer, error) {
+	if c != ClockMonotonic {
+		return nil, fmt.Errorf("Error creating io_uring timer: no timeouts on the %v clock", c)
+	}
+	var p uringParams
+	fd, _, errno := unix.Syscall(unix.SYS_IO_URING_SETUP, uringEntries, uintptr(unsafe.Pointer(&p)), 0)
+	if errno != 0 {
+		return nil, fmt.Errorf("Error creating io_uring instance: %w", errno)
+	}
+	r := &uring{fd: int(fd), sched: relSchedule{now: now}}
+	if err := r.mmap(&p); err != nil {
+		r.close()
+		return nil, err
+	}
+	return &kernelTimer{
+		fd:     -1,
+		ring:   r,
+		done:   make(chan struct{}),
+		exited: make(chan struct{}),
+		errc:   make(chan error, 1),
+	}, nil
+}
+
+// mmap maps the rings the kernel set up, as p describes them.
+func (r *uring) mmap(p *uringParams) error {
+	var err error
+	r.sqRing, err = unix.Mmap(r.fd, ioringOffSQRing, int(p.sqOff.array+p.sqEntries*4), unix.PROT_READ|unix.PROT_WRITE, unix.MAP_SHARED|unix.MAP_POPULATE)
+	if err != nil {
+		return fmt.Errorf("Error mapping io_uring submission ring: %w", err)
+	}
+	r.cqRing, err = unix.Mmap(r.fd, ioringOffCQRing, int(p.cqOff.cqes+p.cqEntries*uint32(unsafe.Sizeof(uringCQE{}))), unix.PROT_READ|unix.PROT_WRITE, unix.MAP_SHARED|unix.MAP_POPULATE)
+	if err != nil {
+		return fmt.Errorf("Error mapping io_uring completion ring: %w", err)
+	}
+	r.sqeMem, err = unix.Mmap(r.fd, ioringOffSQEs, int(p.sqEntries*uint32(unsafe.Sizeof(uringSQE{}))), unix.PROT_READ|unix.PROT_WRITE, unix.MAP_SHARED|unix.MAP_POPULATE)
+	if err != nil {
+		return fmt.Errorf("Error mapping io_uring submission entries: %w", err)
+	}
+	r.sqTail = (*uint32)(unsafe.Pointer(&r.sqRing[p.sqOff.tail]))
+	r.sqMask = (*uint32)(unsafe.Pointer(&r.sqRing[p.sqOff.ringMask]))
+	r.sqArray = unsafe.Pointer(&r.sqRing[p.sqOff.array])
+	r.cqHead = (*uint32)(unsafe.Pointer(&r.cqRing[p.cqOff.head]))
+	r.cqTail = (*uint32)(unsafe.Pointer(&r.cqRing[p.cqOff.tail]))
+	r.cqMask = (*uint32)(unsafe.Pointer(&r.cqRing[p.cqOff.ringMask]))
+	r.cqes = unsafe.Pointer(&r.cqRing[p.cqOff.cqes])
+	return nil
+}
+
+// close unmaps the rings and closes the instance, cancelling its requests.
+func (r *uring) close() {
+	for _, m := range [][]byte{r.sqRing, r.cqRing, r.sqeMem} {
+		if m != nil {
+			unix.Munmap(m)
+		}
+	}
+	unix.Close(r.fd)
+}
+
+// push queues sqe for submission; r.mu must be held. The queue never fills,
+// since every path that queues requests submits them before queueing more.
+func (r *uring) push(sqe uringSQE) {
+	tail := atomic.LoadUint32(r.sqTail)
+	i := tail & *r.sqMask
+	*(*uringSQE)(unsafe.Pointer(&r.sqeMem[uintptr(i)*unsafe.Sizeof(sqe)])) = sqe
+	*(*uint32)(unsafe.Pointer(uintptr(r.sqArray) + uintptr(i)*4)) = i
+	atomic.StoreUint32(r.sqTail, tail+1)
+	r.pending++
+}
+
+// enter submits the queued requests and waits for at least wait completions;
+// r.mu must not be held if wait is non-zero.
+func (r *uring) enter(submit, wait uint32) error {
+	flags := uintptr(0)
+	if wait > 0 {
+		flags = ioringEnterGetevents
+	}
+	_, _, errno := unix.Syscall6(unix.SYS_IO_URING_ENTER, uintptr(r.fd), uintptr(submit), uintptr(wait), flags, 0, 0)
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+// schedule replaces the pending timeout with one expiring wait from now; r.mu
+// must be held. Unless submit is set the requests are left for the timer's
+// goroutine to submit as it next waits.
+func (r *uring) schedule(wait time.Duration, submit bool) error {
+	if r.inFlight {
+		r.push(uringSQE{opcode: ioringOpTimeoutRemove, fd: -1, addr: r.seq, userData: uringRemoveTag})
+	}
+	r.seq++
+	r.ts = kernelTimespec{sec: int64(wait / time.Second), nsec: int64(wait % time.Second)}
+	r.push(uringSQE{opcode: ioringOpTimeout, fd: -1, addr: uint64(uintptr(unsafe.Pointer(&r.ts))), len: 1, userData: r.seq})
+	r.inFlight = true
+	if !submit {
+		return nil
+	}
+	return r.flush()
+}
+
+// flush submits the queued requests; r.mu must be held.
+func (r *uring) flush() error {
+	n := r.pending
+	r.pending = 0
+	if err := r.enter(n, 0); err != nil {
+		return fmt.Errorf("Error submitting io_uring timeout: %w", err)
+	}
+	return nil
+}
+
+// reap consumes the completions, reporting whether the latest timeout
+// expired.
+func (r *uring) reap() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	expired := false
+	head := atomic.LoadUint32(r.cqHead)
+	tail := atomic.LoadUint32(r.cqTail)
+	for ; head != tail; head++ {
+		cqe := (*uringCQE)(unsafe.Pointer(uintptr(r.cqes) + uintptr(head&*r.cqMask)*unsafe.Sizeof(uringCQE{})))
+		if cqe.userData == r.seq {
+			r.inFlight = false
+			expired = cqe.res == -int32(unix.ETIME)
+		}
+	}
+	atomic.StoreUint32(r.cqHead, head)
+	return expired
+}
+
+// wake completes a no-op request, returning the timer's goroutine from its
+// wait.
+func (r *uring) wake() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.push(uringSQE{opcode: ioringOpNop, fd: -1, userData: uringWakeTag})
+	r.flush()
+}
+
+// arm sets the schedule and submits its first timeout.
+func (r *uring) arm(at Time, interval time.Duration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.schedule(r.sched.set(at, interval), true)
+}
+
+// runRing is run for a timer driven by io_uring.
+func (f *kernelTimer) runRing(fire func(n uint64) bool) {
+	r := f.ring
+	for {
+		r.mu.Lock()
+		n := r.pending
+		r.pending = 0
+		r.mu.Unlock()
+		if err := r.enter(n, 1); err != nil {
+			if errors.Is(err, unix.EINTR) {
+				// Nothing was submitted.
+				r.mu.Lock()
+				r.pending += n
+				r.mu.Unlock()
+				continue
+			}
+			f.fail(fmt.Errorf("Error waiting on io_uring: %w", err))
+			return
+		}
+		timedOut := r.reap()
+		select {
+		case <-f.done:
+			return
+		default:
+		}
+		if !timedOut {
+			continue
+		}
+
+		expired, rearm, wait := r.sched.expired()
+		if rearm {
+			r.mu.Lock()
+			r.schedule(wait, false)
+			r.mu.Unlock()
+		}
+		if expired > 0 && !fire(expired) {
+			return
+		}
+	}
+}
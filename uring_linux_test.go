@@ -0,0 +1,85 @@
+package monotime
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// uringTicker returns a ticker created WithIOUring, skipping the test where
+// io_uring is unavailable.
+func uringTicker(t *testing.T, d time.Duration, opts ...TickerOption) *Ticker {
+	t.Helper()
+	ticker, err := NewTickerErr(d, append(opts, WithIOUring())...)
+	if errors.Is(err, unix.ENOSYS) || errors.Is(err, unix.EPERM) {
+		t.Skipf("io_uring unavailable: %v", err)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ticker
+}
+
+func TestIOUringTicker(t *testing.T) {
+	const d = time.Millisecond
+	fds := openFDs(t)
+	ticker := uringTicker(t, d)
+	prev := <-ticker.C
+	for i := 0; i < 20; i++ {
+		select {
+		case due := <-ticker.C:
+			if gap := due.Sub(prev); gap != d {
+				t.Fatalf("tick %d due %v after the previous, want %v", i, gap, d)
+			}
+			prev = due
+		case <-time.After(time.Second):
+			t.Fatalf("tick %d did not arrive", i)
+		}
+	}
+	if next, ok := ticker.NextTick(); !ok || next <= prev {
+		t.Errorf("NextTick() = %v, %v after a tick due %v", next, ok, prev)
+	}
+
+	ticker.Pause()
+	select {
+	case <-ticker.C:
+	case <-time.After(5 * d):
+	}
+	select {
+	case <-ticker.C:
+		t.Fatal("tick while paused")
+	case <-time.After(20 * d):
+	}
+	ticker.Resume()
+	select {
+	case <-ticker.C:
+	case <-time.After(time.Second):
+		t.Fatal("no tick after Resume")
+	}
+
+	ticker.Stop()
+	waitFDs(t, fds)
+}
+
+func TestIOUringFixedRate(t *testing.T) {
+	const d = time.Millisecond
+	ticker := uringTicker(t, d, WithFixedRate())
+	defer ticker.Stop()
+	prev := <-ticker.C
+	for i := 0; i < 10; i++ {
+		due := <-ticker.C
+		if gap := due.Sub(prev); gap%d != 0 {
+			t.Fatalf("tick %d due %v after the previous, want a multiple of %v", i, gap, d)
+		}
+		prev = due
+	}
+}
+
+func TestIOUringOtherClock(t *testing.T) {
+	if ticker, err := NewTickerErr(time.Millisecond, WithClock(ClockBoottime), WithIOUring()); err == nil {
+		ticker.Stop()
+		t.Fatal("io_uring ticker on the boottime clock")
+	}
+}
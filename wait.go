@@ -0,0 +1,43 @@
+package monotime
+
+import (
+	"runtime"
+	"time"
+)
+
+// WaitUntil blocks until the monotonic clock reaches t, more punctually than
+// SleepUntil: it sleeps until spinWindow before t, then busy-polls the clock
+// for the rest of the way. A sleep wakes late by however long the kernel and
+// scheduler take, often 50µs or more; the poll absorbs that, so that WaitUntil
+// returns within a few microseconds of t as long as spinWindow covers the
+// wakeup delay. The CPU is busy only for the window, which should be kept as
+// short as that allows; 100µs to 1ms is typical. If t is not in the future
+// WaitUntil returns immediately.
+func WaitUntil(t Time, spinWindow time.Duration) {
+	SleepUntil(t.Add(-spinWindow))
+	SpinUntil(t)
+}
+
+// spinYieldAbove is how far from its deadline SpinUntil still yields the
+// processor between polls. Closer in, a yield risks handing the thread to a
+// goroutine that runs past the deadline.
+const spinYieldAbove = 20 * time.Microsecond
+
+// SpinUntil busy-polls the monotonic clock until it reaches t, for waits that
+// need sub-microsecond precision and can afford a CPU for their length. The
+// goroutine never sleeps: while t is further off than a few tens of
+// microseconds it yields to other goroutines between polls, as
+// runtime.Gosched does, and then polls without pause. Spin only for short
+// waits; WaitUntil sleeps through most of a longer one. If t is not in the
+// future SpinUntil returns immediately.
+func SpinUntil(t Time) {
+	for {
+		left := Until(t)
+		if left <= 0 {
+			return
+		}
+		if left > spinYieldAbove {
+			runtime.Gosched()
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package monotime
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWaitUntil(t *testing.T) {
+	deadline := Now().Add(5 * time.Millisecond)
+	WaitUntil(deadline, time.Millisecond)
+	if late := Now().Sub(deadline); late < 0 {
+		t.Errorf("WaitUntil returned %v early", -late)
+	}
+
+	start := Now()
+	WaitUntil(start.Add(-time.Second), time.Millisecond)
+	if d := Now().Sub(start); d > 100*time.Millisecond {
+		t.Errorf("WaitUntil in the past took %v", d)
+	}
+}
+
+func TestSpinUntil(t *testing.T) {
+	deadline := Now().Add(200 * time.Microsecond)
+	SpinUntil(deadline)
+	if late := Now().Sub(deadline); late < 0 {
+		t.Errorf("SpinUntil returned %v early", -late)
+	}
+
+	// Other goroutines still run while the spin is far from its deadline.
+	ran := make(chan struct{})
+	go close(ran)
+	SpinUntil(Now().Add(10 * time.Millisecond))
+	select {
+	case <-ran:
+	default:
+		t.Error("goroutine did not run during a 10ms spin")
+	}
+}
@@ -0,0 +1,203 @@
+package monotime
+
+import (
+	"fmt"
+	"sync"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+var (
+	kernel32                   = syscall.NewLazyDLL("kernel32.dll")
+	procCreateWaitableTimerExW = kernel32.NewProc("CreateWaitableTimerExW")
+	procSetWaitableTimer       = kernel32.NewProc("SetWaitableTimer")
+	procCreateEventW           = kernel32.NewProc("CreateEventW")
+	procSetEvent               = kernel32.NewProc("SetEvent")
+	procWaitForMultipleObjects = kernel32.NewProc("WaitForMultipleObjects")
+)
+
+const (
+	createWaitableTimerHighResolution = 0x2
+	timerAllAccess                    = 0x1F0003
+
+	// highResolutionBelow is the interval under which a timer holds the
+	// system timer resolution raised, when the kernel can't give it a high
+	// resolution timer of its own: the default 15.6ms interrupt would make
+	// anything shorter fire late.
+	highResolutionBelow = 16 * time.Millisecond
+)
+
+// kernelTimer is a kernel timer together with the goroutine waiting on it. It
+// is the engine shared by Ticker and Timer; on Windows it is a waitable timer,
+// armed relative to now. Where high resolution waitable timers are missing,
+// short timers hold the system timer resolution raised while they exist.
+type kernelTimer struct {
+	timer   syscall.Handle
+	wake    syscall.Handle // event set by stop to wake the goroutine
+	highRes bool           // whether timer is a high resolution timer
+	sched   relSchedule
+
+	mu     sync.Mutex
+	closed bool   // whether the handles have been released
+	endRes func() // releases the raised system timer resolution, if held
+
+	stopOnce sync.Once
+	done     chan struct{}
+	exited   chan struct{}
+	errc     chan error // receives the error that ended the goroutine, if any
+}
+
+// newKernelTimer returns a kernelTimer on the monotonic clock.
+func newKernelTimer() (*kernelTimer, error) {
+	return newWaitableTimer(Now)
+}
+
+// newWaitableTimer returns a kernelTimer whose schedule is kept on the clock
+// now reads.
+func newWaitableTimer(now func() Time) (*kernelTimer, error) {
+	// High resolution waitable timers need Windows 10 1803; older
+	// versions reject the flag.
+	highRes := true
+	h, _, err := procCreateWaitableTimerExW.Call(0, 0, createWaitableTimerHighResolution, timerAllAccess)
+	if h == 0 {
+		highRes = false
+		h, _, err = procCreateWaitableTimerExW.Call(0, 0, 0, timerAllAccess)
+	}
+	if h == 0 {
+		return nil, fmt.Errorf("Error creating waitable timer: %w", err)
+	}
+	// A manual reset event stays set once stop sets it.
+	wake, _, err := procCreateEventW.Call(0, 1, 0, 0)
+	if wake == 0 {
+		syscall.CloseHandle(syscall.Handle(h))
+		return nil, fmt.Errorf("Error creating wake event: %w", err)
+	}
+	return &kernelTimer{
+		timer:   syscall.Handle(h),
+		wake:    syscall.Handle(wake),
+		highRes: highRes,
+		sched:   relSchedule{now: now},
+		done:    make(chan struct{}),
+		exited:  make(chan struct{}),
+		errc:    make(chan error, 1),
+	}, nil
+}
+
+// arm sets the timer to first expire at the monotonic time at, and then
+// every interval; an interval of zero makes it one-shot. Times that are not
+// in the future expire immediately.
+func (f *kernelTimer) arm(at Time, interval time.Duration) error {
+	wait := f.sched.set(at, interval)
+	if !f.highRes && (interval != 0 && interval < highResolutionBelow || interval == 0 && wait < highResolutionBelow) {
+		f.raiseResolution()
+	}
+	return f.schedule(wait)
+}
+
+// raiseResolution holds the system timer resolution raised until the timer is
+// released.
+func (f *kernelTimer) raiseResolution() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.endRes != nil || f.closed {
+		return
+	}
+	if end, err := HighResolution(); err == nil {
+		f.endRes = end
+	}
+}
+
+// schedule arms the waitable timer to expire once, wait from now.
+func (f *kernelTimer) schedule(wait time.Duration) error {
+	// Negative due times are relative, in 100ns units.
+	due := -int64((wait + 99) / 100)
+	r, _, err := procSetWaitableTimer.Call(uintptr(f.timer), uintptr(unsafe.Pointer(&due)), 0, 0, 0, 0)
+	if r == 0 {
+		return fmt.Errorf("Error arming waitable timer: %w", err)
+	}
+	return nil
+}
+
+// disarm stops the timer expiring until it is armed again. A wakeup already
+// scheduled is left to find nothing due.
+func (f *kernelTimer) disarm() error {
+	f.sched.clear()
+	return nil
+}
+
+// remaining returns the time until the timer next expires, and false if it is
+// not armed.
+func (f *kernelTimer) remaining() (time.Duration, bool, error) {
+	left, armed := f.sched.remaining()
+	return left, armed, nil
+}
+
+// start runs fire on a new goroutine each time the timer expires, with the
+// number of expirations since the last call, until fire returns false or stop
+// is called. The timer is released when the goroutine exits, so one-shot
+// timers don't hold their handles until stopped.
+func (f *kernelTimer) start(fire func(n uint64) bool) {
+	go f.run(fire)
+}
+
+// stop ends the goroutine and releases the timer. Once stop returns fire is
+// not running and will not be called again. stop must not be called from
+// within fire.
+func (f *kernelTimer) stop() {
+	f.stopOnce.Do(func() {
+		close(f.done)
+		f.mu.Lock()
+		if !f.closed {
+			procSetEvent.Call(uintptr(f.wake))
+		}
+		f.mu.Unlock()
+		<-f.exited
+	})
+}
+
+// release closes the handles of a timer whose goroutine is not running.
+// Calling release more than once has no further effect.
+func (f *kernelTimer) release() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.closed {
+		return
+	}
+	f.closed = true
+	syscall.CloseHandle(f.timer)
+	syscall.CloseHandle(f.wake)
+	if f.endRes != nil {
+		f.endRes()
+		f.endRes = nil
+	}
+}
+
+func (f *kernelTimer) run(fire func(n uint64) bool) {
+	defer close(f.exited)
+	defer f.release()
+
+	handles := [2]syscall.Handle{f.timer, f.wake}
+	for {
+		r, _, err := procWaitForMultipleObjects.Call(2, uintptr(unsafe.Pointer(&handles[0])), 0, syscall.INFINITE)
+		switch r {
+		case syscall.WAIT_OBJECT_0:
+		case syscall.WAIT_OBJECT_0 + 1:
+			return
+		default:
+			f.fail(fmt.Errorf("Error waiting on waitable timer: %w", err))
+			return
+		}
+
+		expired, rearm, wait := f.sched.expired()
+		if rearm {
+			if err := f.schedule(wait); err != nil {
+				f.fail(err)
+				return
+			}
+		}
+		if expired > 0 && !fire(expired) {
+			return
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package monotime
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"golang.org/x/sys/unix"
+)
+
+// WallClockWatcher reports steps of the system wall clock: it is set, as by
+// settimeofday or clock_settime, rather than slewed by NTP. Applications that
+// map between the wall and monotonic clocks can re-anchor the mapping when
+// one comes. The kernel may report other discontinuous changes too, such as
+// on resume from suspend.
+type WallClockWatcher struct {
+	// C receives a ClockSample taken just after each step. It holds only
+	// the latest; an unread sample is replaced by the next.
+	C <-chan ClockSample
+
+	c    chan ClockSample
+	kt   *kernelTimer
+	copy copyCheck
+}
+
+// WatchWallClock returns a WallClockWatcher, backed by a CLOCK_REALTIME
+// timerfd armed with TFD_TIMER_CANCEL_ON_SET, which the kernel cancels
+// whenever the clock is set. Stop the watcher to release it.
+func WatchWallClock() (*WallClockWatcher, error) {
+	kt, err := newTimerfd(unix.CLOCK_REALTIME)
+	if err != nil {
+		return nil, err
+	}
+	if err := armCancelOnSet(kt); err != nil {
+		kt.release()
+		return nil, err
+	}
+	c := make(chan ClockSample, 1)
+	w := &WallClockWatcher{C: c, c: c, kt: kt}
+	w.copy.init()
+	go w.run()
+	return w, nil
+}
+
+// armCancelOnSet arms kt, a CLOCK_REALTIME timerfd, to expire never, and be
+// cancelled when the clock is set.
+func armCancelOnSet(kt *kernelTimer) error {
+	spec := unix.ItimerSpec{Value: unix.NsecToTimespec(math.MaxInt64)}
+	if err := unix.TimerfdSettime(kt.fd, unix.TFD_TIMER_ABSTIME|unix.TFD_TIMER_CANCEL_ON_SET, &spec, nil); err != nil {
+		return fmt.Errorf("Error arming wall clock timerfd: %w", err)
+	}
+	return nil
+}
+
+func (w *WallClockWatcher) run() {
+	defer close(w.kt.exited)
+	defer w.kt.release()
+
+	var buf [8]byte
+	for {
+		_, err := w.kt.file.Read(buf[:])
+		select {
+		case <-w.kt.done:
+			return
+		default:
+		}
+		stepped := errors.Is(err, unix.ECANCELED)
+		if err != nil && !stepped {
+			w.kt.fail(fmt.Errorf("Error reading wall clock timerfd: %w", err))
+			return
+		}
+		// A cancelled timer stays readable until re-armed. Re-arming
+		// before sampling means a step during the sample is reported
+		// too.
+		if err := armCancelOnSet(w.kt); err != nil {
+			w.kt.fail(err)
+			return
+		}
+		if stepped {
+			w.send(SampleClocks())
+		}
+	}
+}
+
+// send delivers s, replacing an unread sample. Only the watcher's goroutine
+// sends, so after discarding the stale one the next send succeeds.
+func (w *WallClockWatcher) send(s ClockSample) {
+	for {
+		select {
+		case w.c <- s:
+			return
+		default:
+		}
+		select {
+		case <-w.c:
+		default:
+		}
+	}
+}
+
+// Err returns a channel that receives the error that ended the watcher,
+// should its timerfd fail. No more steps are reported after a failure.
+func (w *WallClockWatcher) Err() <-chan error {
+	return w.kt.errc
+}
+
+// Stop turns off the watcher and releases its timerfd. Calling Stop more than
+// once has no further effect.
+func (w *WallClockWatcher) Stop() {
+	w.copy.check("WallClockWatcher.Stop")
+	w.kt.stop()
+}
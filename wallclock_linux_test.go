@@ -0,0 +1,51 @@
+package monotime
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+func TestWatchWallClock(t *testing.T) {
+	before := openFDs(t)
+	w, err := WatchWallClock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case s := <-w.C:
+		t.Fatalf("step reported with none made: %+v", s)
+	case err := <-w.Err():
+		t.Fatal(err)
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	// Setting the clock to the time it already reads is a step of under
+	// a microsecond, but a step all the same.
+	var tv unix.Timeval
+	if err := unix.Gettimeofday(&tv); err != nil {
+		t.Fatal(err)
+	}
+	if err := unix.Settimeofday(&tv); errors.Is(err, unix.EPERM) {
+		t.Log("no CAP_SYS_TIME to step the clock")
+	} else if err != nil {
+		t.Fatal(err)
+	} else {
+		select {
+		case s := <-w.C:
+			if s.Realtime.IsZero() {
+				t.Error("step reported with an empty sample")
+			}
+		case err := <-w.Err():
+			t.Fatal(err)
+		case <-time.After(5 * time.Second):
+			t.Fatal("no step reported")
+		}
+	}
+
+	w.Stop()
+	w.Stop()
+	waitFDs(t, before)
+}